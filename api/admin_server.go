@@ -0,0 +1,658 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"rate-limiter-go/limiter"
+	"rate-limiter-go/persist"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrMissingServiceID = errors.New("service id is required")
+var ErrInvalidServiceID = errors.New("service id should only contain letters, digits, '_', '-', '.' and ':' and not start with '.'")
+var ErrCreateServiceIdCollision = errors.New("a service already exists with this id")
+var ErrMissingRule = errors.New("rule is required")
+var ErrMissingRuleID = errors.New("rule id is required")
+
+// serviceIDPattern matches the service ids the admin API accepts. Service
+// ids are used as file names when services are persisted.
+var serviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_:-][A-Za-z0-9_.:-]*$`)
+
+// ruleSetFileName is the name the rule set is persisted under.
+const ruleSetFileName = "rules"
+
+// ServiceRecord is how a service changed through the admin API is persisted.
+// A deleted record is the tombstone of a service that was deleted while the
+// config file still defines it, so that it is not created again on start.
+type ServiceRecord struct {
+	limiter.Service
+	Deleted bool `json:"deleted,omitempty"`
+	// ConfigHash identifies the config file's definition of the service the
+	// change was made against.
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// RuleSetRecord is how the rule set is persisted once it was changed through
+// the admin API.
+type RuleSetRecord struct {
+	limiter.RuleSet
+	// ConfigHash identifies the rules of the config file the rule set was
+	// changed from.
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// AdminServer manages the rate limiter's configuration at runtime. Changes
+// are written with ServiceWriter and RuleWriter when they are set, so that
+// they survive a restart.
+type AdminServer struct {
+	UnimplementedRateLimiterAdminServer
+	ServiceRegistry limiter.ServiceRegistry
+	RuleRegistry    limiter.RuleRegistry
+	BucketStorage   limiter.BucketStorage
+	ServiceWriter   persist.FileWriter[ServiceRecord]
+	RuleWriter      persist.FileWriter[RuleSetRecord]
+	// ConfigServices and ConfigRules are the services, by id, and the rules
+	// defined by the config file. ApplyConfig replaces them with the ones of
+	// the reloaded file.
+	ConfigServices map[string]limiter.Service
+	ConfigRules    []limiter.Rule
+	// mu serializes changes, so that the registry and the persisted files
+	// change in the same order.
+	mu sync.Mutex
+}
+
+func (s *AdminServer) CreateService(ctx context.Context, req *CreateServiceRequest) (*Service, error) {
+	log.Printf("level=info event=admin_create_service service_id=%q usage_price_in_tokens=%d", req.ServiceID, req.UsagePriceInTokens)
+	if req.ServiceID == "" {
+		return nil, ErrMissingServiceID
+	}
+	if !serviceIDPattern.MatchString(req.ServiceID) {
+		log.Printf("level=error event=admin_create_service status=error service_id=%q: error=%q", req.ServiceID, ErrInvalidServiceID)
+		return nil, ErrInvalidServiceID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err == nil {
+		log.Printf("level=error event=admin_create_service status=error service_id=%q: error=%q", req.ServiceID, ErrCreateServiceIdCollision)
+		return nil, ErrCreateServiceIdCollision
+	}
+	service, err := s.ServiceRegistry.CreateService(limiter.CreateServiceReqBody{
+		ID:                 req.ServiceID,
+		UsagePriceInTokens: req.UsagePriceInTokens,
+	})
+	if err != nil {
+		log.Printf("level=error event=admin_create_service status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+	err = s.saveService(service)
+	if err != nil {
+		return nil, err
+	}
+	return toServiceMessage(service), nil
+}
+
+func (s *AdminServer) UpdateService(ctx context.Context, req *UpdateServiceRequest) (*Service, error) {
+	log.Printf("level=info event=admin_update_service service_id=%q usage_price_in_tokens=%d", req.ServiceID, req.UsagePriceInTokens)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	service, err := s.ServiceRegistry.UpdateService(req.ServiceID, limiter.UpdateServiceReqBody{
+		UsagePriceInTokens: req.UsagePriceInTokens,
+	})
+	if err != nil {
+		log.Printf("level=error event=admin_update_service status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+	err = s.saveService(service)
+	if err != nil {
+		return nil, err
+	}
+	return toServiceMessage(service), nil
+}
+
+func (s *AdminServer) GetService(ctx context.Context, req *GetServiceRequest) (*Service, error) {
+	service, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	return toServiceMessage(service), nil
+}
+
+func (s *AdminServer) ListServices(ctx context.Context, req *ListServicesRequest) (*ListServicesResponse, error) {
+	services := s.ServiceRegistry.ListServices()
+	res := &ListServicesResponse{Services: make([]*Service, 0, len(services))}
+	for _, service := range services {
+		res.Services = append(res.Services, toServiceMessage(service))
+	}
+	return res, nil
+}
+
+// DeleteService removes a service. Deleting a service the config file
+// defines leaves a tombstone, so that it is not created again on start.
+func (s *AdminServer) DeleteService(ctx context.Context, req *DeleteServiceRequest) (*DeleteServiceResponse, error) {
+	log.Printf("level=info event=admin_delete_service service_id=%q", req.ServiceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.ServiceRegistry.DeleteService(req.ServiceID)
+	if err == limiter.ErrServiceNotFound {
+		return &DeleteServiceResponse{Deleted: false}, nil
+	}
+	if err != nil {
+		log.Printf("level=error event=admin_delete_service status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+	if s.ServiceWriter != nil {
+		if _, inConfig := s.ConfigServices[req.ServiceID]; inConfig {
+			err = s.saveServiceRecord(ServiceRecord{Service: limiter.Service{ID: req.ServiceID}, Deleted: true})
+		} else {
+			err = s.ServiceWriter.DeleteFile(req.ServiceID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &DeleteServiceResponse{Deleted: true}, nil
+}
+
+func (s *AdminServer) saveService(service limiter.Service) error {
+	return s.saveServiceRecord(ServiceRecord{Service: service})
+}
+
+func (s *AdminServer) saveServiceRecord(record ServiceRecord) error {
+	if s.ServiceWriter == nil {
+		return nil
+	}
+	record.ConfigHash = s.configServiceHash(record.ID)
+	err := s.ServiceWriter.SaveToFile(&record, record.ID)
+	if err != nil {
+		log.Printf("level=error event=persist_service status=error service_id=%q deleted=%t: error=%q", record.ID, record.Deleted, err)
+	}
+	return err
+}
+
+func toServiceMessage(service limiter.Service) *Service {
+	return &Service{
+		ServiceID:          service.ID,
+		UsagePriceInTokens: service.UsagePriceInTokens,
+	}
+}
+
+func (s *AdminServer) GetBucket(ctx context.Context, req *GetBucketRequest) (*Bucket, error) {
+	b, err := s.BucketStorage.GetBucket(req.BucketID)
+	if err != nil {
+		return nil, err
+	}
+	return toBucketMessage(b)
+}
+
+// ListBuckets returns the buckets in the order of their ids, a page at a
+// time. Pass the nextCursor of a response as the cursor of the next request
+// to get the next page.
+func (s *AdminServer) ListBuckets(ctx context.Context, req *ListBucketsRequest) (*ListBucketsResponse, error) {
+	buckets, nextCursor := s.BucketStorage.ListBuckets(limiter.ListBucketsRequest{
+		ServiceID: req.ServiceID,
+		ClientID:  req.ClientID,
+		Cursor:    req.Cursor,
+		Limit:     int(req.Limit),
+	})
+	res := &ListBucketsResponse{
+		Buckets:    make([]*Bucket, 0, len(buckets)),
+		NextCursor: nextCursor,
+	}
+	for _, b := range buckets {
+		msg, err := toBucketMessage(b)
+		if err != nil {
+			return nil, err
+		}
+		res.Buckets = append(res.Buckets, msg)
+	}
+	return res, nil
+}
+
+func (s *AdminServer) ResetBucket(ctx context.Context, req *ResetBucketRequest) (*Bucket, error) {
+	log.Printf("level=info event=admin_reset_bucket bucket_id=%q", req.BucketID)
+	err := s.BucketStorage.ResetBucket(req.BucketID)
+	if err != nil {
+		log.Printf("level=error event=admin_reset_bucket status=error bucket_id=%q: error=%q", req.BucketID, err)
+		return nil, err
+	}
+	return s.GetBucket(ctx, &GetBucketRequest{BucketID: req.BucketID})
+}
+
+func (s *AdminServer) SetBucketTokens(ctx context.Context, req *SetBucketTokensRequest) (*Bucket, error) {
+	log.Printf("level=info event=admin_set_bucket_tokens bucket_id=%q tokens=%d", req.BucketID, req.Tokens)
+	err := s.BucketStorage.SetBucketTokens(req.BucketID, req.Tokens)
+	if err != nil {
+		log.Printf("level=error event=admin_set_bucket_tokens status=error bucket_id=%q: error=%q", req.BucketID, err)
+		return nil, err
+	}
+	return s.GetBucket(ctx, &GetBucketRequest{BucketID: req.BucketID})
+}
+
+// DeleteBucket removes a bucket, which is created again, full, by the next
+// request limited by it. Its persisted file is removed on the next save.
+func (s *AdminServer) DeleteBucket(ctx context.Context, req *DeleteBucketRequest) (*DeleteBucketResponse, error) {
+	log.Printf("level=info event=admin_delete_bucket bucket_id=%q", req.BucketID)
+	err := s.BucketStorage.DeleteBucket(req.BucketID)
+	if err == limiter.ErrBucketNotFound {
+		return &DeleteBucketResponse{Deleted: false}, nil
+	}
+	if err != nil {
+		log.Printf("level=error event=admin_delete_bucket status=error bucket_id=%q: error=%q", req.BucketID, err)
+		return nil, err
+	}
+	return &DeleteBucketResponse{Deleted: true}, nil
+}
+
+func toBucketMessage(b *limiter.Bucket) (*Bucket, error) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	l, err := b.Limiter()
+	if err != nil {
+		return nil, err
+	}
+	status := l.Check(0, time.Now())
+	return &Bucket{
+		BucketID:          b.ID,
+		Key:               b.Key,
+		Level:             string(b.Level),
+		LimitName:         b.LimitName,
+		RuleID:            b.RuleID,
+		ServiceID:         b.ServiceID,
+		ClientID:          b.ClientID,
+		Algorithm:         string(b.Algorithm),
+		Remaining:         status.Remaining,
+		Limit:             status.Limit,
+		ResetAfterSeconds: status.ResetAfterSeconds(),
+		CreatedAtUnix:     b.CreatedAt.Unix(),
+	}, nil
+}
+
+// CreateRule adds a rule. It applies to buckets created from then on;
+// existing buckets keep the rule they were created for.
+func (s *AdminServer) CreateRule(ctx context.Context, req *CreateRuleRequest) (*RuleChangeResponse, error) {
+	rule, err := fromRuleMessage(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("level=info event=admin_create_rule rule_id=%q", rule.ID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.RuleRegistry.GetRule(rule.ID)
+	if err == nil {
+		log.Printf("level=error event=admin_create_rule status=error rule_id=%q: error=%q", rule.ID, limiter.ErrCreateRuleIdCollision)
+		return nil, limiter.ErrCreateRuleIdCollision
+	}
+	err = s.RuleRegistry.AddRule(rule)
+	if err != nil {
+		log.Printf("level=error event=admin_create_rule status=error rule_id=%q: error=%q", rule.ID, err)
+		return nil, err
+	}
+	return s.rulesChanged(rule, 0, 0)
+}
+
+// UpdateRule replaces a rule. With migrateBuckets, the buckets created for
+// the rule are moved onto its new limits; otherwise they keep the limits
+// they were created with.
+func (s *AdminServer) UpdateRule(ctx context.Context, req *UpdateRuleRequest) (*RuleChangeResponse, error) {
+	rule, err := fromRuleMessage(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("level=info event=admin_update_rule rule_id=%q migrate_buckets=%t", rule.ID, req.MigrateBuckets)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err = s.RuleRegistry.UpdateRule(rule)
+	if err != nil {
+		log.Printf("level=error event=admin_update_rule status=error rule_id=%q: error=%q", rule.ID, err)
+		return nil, err
+	}
+	var migrated, deleted int
+	if req.MigrateBuckets {
+		migrated, deleted = s.BucketStorage.MigrateRuleBuckets(rule.ID, rule.BucketLimits())
+	}
+	return s.rulesChanged(rule, migrated, deleted)
+}
+
+// DeleteRule removes a rule. With migrateBuckets, the buckets created for
+// the rule are deleted so that the next request matches another rule;
+// otherwise they keep the limits they were created with.
+func (s *AdminServer) DeleteRule(ctx context.Context, req *DeleteRuleRequest) (*RuleChangeResponse, error) {
+	log.Printf("level=info event=admin_delete_rule rule_id=%q migrate_buckets=%t", req.RuleID, req.MigrateBuckets)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, err := s.RuleRegistry.GetRule(req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.RuleRegistry.RemoveRule(req.RuleID)
+	if err != nil {
+		log.Printf("level=error event=admin_delete_rule status=error rule_id=%q: error=%q", req.RuleID, err)
+		return nil, err
+	}
+	var deleted int
+	if req.MigrateBuckets {
+		_, deleted = s.BucketStorage.MigrateRuleBuckets(rule.ID, nil)
+	}
+	return s.rulesChanged(rule, 0, deleted)
+}
+
+func (s *AdminServer) GetRule(ctx context.Context, req *GetRuleRequest) (*Rule, error) {
+	rule, err := s.RuleRegistry.GetRule(req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+	return toRuleMessage(rule), nil
+}
+
+// ListRules returns the rules in the order they are matched in, and the
+// version of the rule set.
+func (s *AdminServer) ListRules(ctx context.Context, req *ListRulesRequest) (*ListRulesResponse, error) {
+	ruleSet := s.RuleRegistry.GetRuleSet()
+	res := &ListRulesResponse{
+		Rules:   make([]*Rule, 0, len(ruleSet.Rules)),
+		Version: ruleSet.Version,
+	}
+	for _, rule := range ruleSet.Rules {
+		res.Rules = append(res.Rules, toRuleMessage(rule))
+	}
+	return res, nil
+}
+
+// rulesChanged lets scopes without buckets match the changed rules and
+// persists the new rule set. It expects s.mu to be held.
+func (s *AdminServer) rulesChanged(rule limiter.Rule, migrated int, deleted int) (*RuleChangeResponse, error) {
+	s.BucketStorage.ForgetUnmatchedScopes()
+	ruleSet := s.RuleRegistry.GetRuleSet()
+	log.Printf("level=info event=rules_changed rule_id=%q version=%d migrated_buckets=%d deleted_buckets=%d", rule.ID, ruleSet.Version, migrated, deleted)
+	if s.RuleWriter != nil {
+		record := RuleSetRecord{RuleSet: ruleSet, ConfigHash: configHash(s.ConfigRules)}
+		err := s.RuleWriter.SaveToFile(&record, ruleSetFileName)
+		if err != nil {
+			log.Printf("level=error event=persist_rules status=error version=%d: error=%q", ruleSet.Version, err)
+			return nil, err
+		}
+	}
+	return &RuleChangeResponse{
+		Rule:            toRuleMessage(rule),
+		Version:         ruleSet.Version,
+		MigratedBuckets: uint64(migrated),
+		DeletedBuckets:  uint64(deleted),
+	}, nil
+}
+
+func fromRuleMessage(msg *Rule) (limiter.Rule, error) {
+	if msg == nil {
+		return limiter.Rule{}, ErrMissingRule
+	}
+	if msg.RuleID == "" {
+		return limiter.Rule{}, ErrMissingRuleID
+	}
+	rule := limiter.Rule{
+		ID:                  msg.RuleID,
+		Level:               limiter.Level(msg.Level),
+		ServiceID:           msg.ServiceID,
+		ClientID:            msg.ClientID,
+		OrgID:               msg.OrgID,
+		UserID:              msg.UserID,
+		UserTier:            msg.UserTier,
+		Algorithm:           limiter.Algorithm(msg.Algorithm),
+		RefillRatePerSecond: msg.RefillRatePerSecond,
+		InitialTokens:       msg.InitialTokens,
+		MaxTokens:           msg.MaxTokens,
+		WindowSeconds:       msg.WindowSeconds,
+		Period:              limiter.Period(msg.Period),
+		TimeZone:            msg.TimeZone,
+		MaxConcurrent:       msg.MaxConcurrent,
+		LeaseTTLSeconds:     msg.LeaseTTLSeconds,
+	}
+	for _, limit := range msg.Limits {
+		rule.Limits = append(rule.Limits, limiter.Limit{
+			Name:                limit.Name,
+			Algorithm:           limiter.Algorithm(limit.Algorithm),
+			RefillRatePerSecond: limit.RefillRatePerSecond,
+			InitialTokens:       limit.InitialTokens,
+			MaxTokens:           limit.MaxTokens,
+			WindowSeconds:       limit.WindowSeconds,
+			Period:              limiter.Period(limit.Period),
+			TimeZone:            limit.TimeZone,
+		})
+	}
+	err := limiter.ValidateRule(rule)
+	if err != nil {
+		log.Printf("level=error event=validate_rule status=error rule_id=%q: error=%q", rule.ID, err)
+		return limiter.Rule{}, err
+	}
+	return rule, nil
+}
+
+func toRuleMessage(rule limiter.Rule) *Rule {
+	msg := &Rule{
+		RuleID:              rule.ID,
+		Level:               string(rule.Level),
+		ServiceID:           rule.ServiceID,
+		ClientID:            rule.ClientID,
+		OrgID:               rule.OrgID,
+		UserID:              rule.UserID,
+		UserTier:            rule.UserTier,
+		Algorithm:           string(rule.Algorithm),
+		RefillRatePerSecond: rule.RefillRatePerSecond,
+		InitialTokens:       rule.InitialTokens,
+		MaxTokens:           rule.MaxTokens,
+		WindowSeconds:       rule.WindowSeconds,
+		Period:              string(rule.Period),
+		TimeZone:            rule.TimeZone,
+		MaxConcurrent:       rule.MaxConcurrent,
+		LeaseTTLSeconds:     rule.LeaseTTLSeconds,
+	}
+	for _, limit := range rule.Limits {
+		msg.Limits = append(msg.Limits, &RuleLimit{
+			Name:                limit.Name,
+			Algorithm:           string(limit.Algorithm),
+			RefillRatePerSecond: limit.RefillRatePerSecond,
+			InitialTokens:       limit.InitialTokens,
+			MaxTokens:           limit.MaxTokens,
+			WindowSeconds:       limit.WindowSeconds,
+			Period:              string(limit.Period),
+			TimeZone:            limit.TimeZone,
+		})
+	}
+	return msg
+}
+
+// ApplyConfig swaps in the services and rules of a reloaded config file.
+// Services are created or updated from services, and the ones listed in
+// removedServiceIDs are deleted; every other service is kept. When the rules
+// of the file changed they replace every rule, including the ones changed
+// through the admin API, and the buckets of changed and removed rules are
+// migrated like UpdateRule and DeleteRule do with migrateBuckets. It returns
+// the version of the rule set.
+//
+// Like on start, the file only wins over changes made through the admin API
+// for the parts of it that changed: the persisted changes those parts
+// replace are deleted, and the others are left alone.
+func (s *AdminServer) ApplyConfig(services []limiter.Service, removedServiceIDs []string, rules []limiter.Rule, defaultRule limiter.Rule) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	servicesByID := make(map[string]limiter.Service)
+	for _, service := range s.ServiceRegistry.ListServices() {
+		servicesByID[service.ID] = service
+	}
+	changedServiceIDs := make([]string, 0)
+	for _, id := range removedServiceIDs {
+		delete(servicesByID, id)
+		changedServiceIDs = append(changedServiceIDs, id)
+	}
+	for _, service := range services {
+		if configHash(service) == s.configServiceHash(service.ID) {
+			continue
+		}
+		servicesByID[service.ID] = service
+		changedServiceIDs = append(changedServiceIDs, service.ID)
+	}
+	if len(changedServiceIDs) > 0 {
+		allServices := make([]limiter.Service, 0, len(servicesByID))
+		for _, service := range servicesByID {
+			allServices = append(allServices, service)
+		}
+		s.ServiceRegistry.ReplaceServices(allServices)
+	}
+	s.ConfigServices = make(map[string]limiter.Service, len(services))
+	for _, service := range services {
+		s.ConfigServices[service.ID] = service
+	}
+
+	ruleSet := s.RuleRegistry.GetRuleSet()
+	oldDefaultRule := s.RuleRegistry.GetDefaultRule()
+	rulesChanged := configHash(rules) != configHash(s.ConfigRules)
+	s.ConfigRules = rules
+	if !rulesChanged {
+		rules = ruleSet.Rules
+	}
+	version := ruleSet.Version
+	if rulesChanged || !reflect.DeepEqual(defaultRule, oldDefaultRule) {
+		version = s.RuleRegistry.ReplaceRules(rules, defaultRule)
+		s.BucketStorage.ForgetUnmatchedScopes()
+		migrated, deleted := s.migrateChangedRules(append(ruleSet.Rules, oldDefaultRule), append(slices.Clone(rules), defaultRule))
+		log.Printf("level=info event=apply_config version=%d migrated_buckets=%d deleted_buckets=%d", version, migrated, deleted)
+	}
+	log.Printf("level=info event=apply_config version=%d rules_changed=%t changed_services=%d", version, rulesChanged, len(changedServiceIDs))
+
+	if s.ServiceWriter != nil {
+		for _, id := range changedServiceIDs {
+			err := s.ServiceWriter.DeleteFile(id)
+			if err != nil {
+				return version, err
+			}
+		}
+	}
+	if s.RuleWriter != nil && rulesChanged {
+		err := s.RuleWriter.DeleteFile(ruleSetFileName)
+		if err != nil {
+			return version, err
+		}
+	}
+	return version, nil
+}
+
+// migrateChangedRules migrates the buckets of the old rules that changed or
+// are not among the new rules anymore. It returns how many buckets were
+// changed and how many were deleted.
+func (s *AdminServer) migrateChangedRules(oldRules []limiter.Rule, newRules []limiter.Rule) (migrated int, deleted int) {
+	for _, old := range oldRules {
+		i := slices.IndexFunc(newRules, func(r limiter.Rule) bool { return r.ID == old.ID })
+		var limits []limiter.Limit
+		if i >= 0 {
+			if reflect.DeepEqual(newRules[i].BucketLimits(), old.BucketLimits()) {
+				continue
+			}
+			limits = newRules[i].BucketLimits()
+		}
+		m, d := s.BucketStorage.MigrateRuleBuckets(old.ID, limits)
+		migrated += m
+		deleted += d
+	}
+	return migrated, deleted
+}
+
+// RestorePersisted applies the changes persisted by earlier runs on top of
+// the config file. A change made against another version of the config file
+// than the current one is dropped, since the file was edited after it.
+func (s *AdminServer) RestorePersisted() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.RuleWriter != nil {
+		ruleSets, err := s.RuleWriter.LoadAll()
+		if err != nil {
+			return err
+		}
+		for _, ruleSet := range ruleSets {
+			if ruleSet.ConfigHash != "" && ruleSet.ConfigHash != configHash(s.ConfigRules) {
+				log.Printf("level=warn event=restore_rule_set status=skipped version=%d reason=config_file_changed", ruleSet.Version)
+				err := s.RuleWriter.DeleteFile(ruleSetFileName)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if err := validateRules(ruleSet.Rules); err != nil {
+				// The rules of the config file stay in use
+				log.Printf("level=error event=restore_rule_set status=skipped version=%d reason=invalid_rule err=%q", ruleSet.Version, err)
+				continue
+			}
+			log.Printf("event=restore_rule_set version=%d rules=%d", ruleSet.Version, len(ruleSet.Rules))
+			s.RuleRegistry.RestoreRuleSet(ruleSet.RuleSet)
+		}
+	}
+	if s.ServiceWriter != nil {
+		services, err := s.ServiceWriter.LoadAll()
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			if service.ConfigHash != "" && service.ConfigHash != s.configServiceHash(service.ID) {
+				log.Printf("level=warn event=restore_service status=skipped id=%q reason=config_file_changed", service.ID)
+				err := s.ServiceWriter.DeleteFile(service.ID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if service.Deleted {
+				log.Printf("event=restore_service id=%q deleted=true", service.ID)
+				_ = s.ServiceRegistry.DeleteService(service.ID)
+				continue
+			}
+			log.Printf("event=restore_service id=%q usage_price_in_tokens=%d", service.ID, service.UsagePriceInTokens)
+			_, err := s.ServiceRegistry.CreateService(limiter.CreateServiceReqBody{
+				ID:                 service.ID,
+				UsagePriceInTokens: service.UsagePriceInTokens,
+			})
+			if err != nil {
+				log.Printf("event=restore_service status=error error=%q", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateRules checks rules like the admin API checks the rules it is
+// given.
+func validateRules(rules []limiter.Rule) error {
+	for _, rule := range rules {
+		err := limiter.ValidateRule(rule)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// configHash identifies a part of the config file, so that persisted changes
+// can be told to be made against another version of it.
+func configHash(v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// configServiceHash identifies the definition of a service in the config
+// file, which may be none.
+func (s *AdminServer) configServiceHash(id string) string {
+	service, ok := s.ConfigServices[id]
+	if !ok {
+		return configHash(nil)
+	}
+	return configHash(service)
+}
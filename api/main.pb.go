@@ -22,13 +22,15 @@ const (
 )
 
 type GetAccessStatusRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ClientID       string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
-	ServiceID      string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
-	UserID         string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
-	UsageAmountReq uint64                 `protobuf:"varint,4,opt,name=usageAmountReq,proto3" json:"usageAmountReq,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClientID        string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ServiceID       string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UserID          string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	UsageAmountReq  uint64                 `protobuf:"varint,4,opt,name=usageAmountReq,proto3" json:"usageAmountReq,omitempty"`
+	UserTier        string                 `protobuf:"bytes,5,opt,name=userTier,proto3" json:"userTier,omitempty"`
+	WaitForCapacity bool                   `protobuf:"varint,6,opt,name=waitForCapacity,proto3" json:"waitForCapacity,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetAccessStatusRequest) Reset() {
@@ -89,12 +91,34 @@ func (x *GetAccessStatusRequest) GetUsageAmountReq() uint64 {
 	return 0
 }
 
+func (x *GetAccessStatusRequest) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+func (x *GetAccessStatusRequest) GetWaitForCapacity() bool {
+	if x != nil {
+		return x.WaitForCapacity
+	}
+	return false
+}
+
 type GetAccessStatusResponse struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	IsAllowed         bool                   `protobuf:"varint,1,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
-	RetryAfterSeconds uint64                 `protobuf:"varint,2,opt,name=retryAfterSeconds,proto3" json:"retryAfterSeconds,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	IsAllowed              bool                   `protobuf:"varint,1,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
+	RetryAfterSeconds      uint64                 `protobuf:"varint,2,opt,name=retryAfterSeconds,proto3" json:"retryAfterSeconds,omitempty"`
+	DelayMilliseconds      uint64                 `protobuf:"varint,3,opt,name=delayMilliseconds,proto3" json:"delayMilliseconds,omitempty"`
+	DeniedLimit            string                 `protobuf:"bytes,4,opt,name=deniedLimit,proto3" json:"deniedLimit,omitempty"`
+	DeniedLevel            string                 `protobuf:"bytes,5,opt,name=deniedLevel,proto3" json:"deniedLevel,omitempty"`
+	Remaining              uint64                 `protobuf:"varint,6,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Limit                  uint64                 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	ResetAfterSeconds      uint64                 `protobuf:"varint,8,opt,name=resetAfterSeconds,proto3" json:"resetAfterSeconds,omitempty"`
+	RuleID                 string                 `protobuf:"bytes,9,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	RetryAfterMilliseconds uint64                 `protobuf:"varint,10,opt,name=retryAfterMilliseconds,proto3" json:"retryAfterMilliseconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *GetAccessStatusResponse) Reset() {
@@ -141,21 +165,2903 @@ func (x *GetAccessStatusResponse) GetRetryAfterSeconds() uint64 {
 	return 0
 }
 
-var File_api_main_proto protoreflect.FileDescriptor
+func (x *GetAccessStatusResponse) GetDelayMilliseconds() uint64 {
+	if x != nil {
+		return x.DelayMilliseconds
+	}
+	return 0
+}
 
-const file_api_main_proto_rawDesc = "" +
-	"\n" +
-	"\x0eapi/main.proto\"\x92\x01\n" +
-	"\x16GetAccessStatusRequest\x12\x1a\n" +
-	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
-	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
-	"\x06userID\x18\x03 \x01(\tR\x06userID\x12&\n" +
-	"\x0eusageAmountReq\x18\x04 \x01(\x04R\x0eusageAmountReq\"e\n" +
-	"\x17GetAccessStatusResponse\x12\x1c\n" +
-	"\tisAllowed\x18\x01 \x01(\bR\tisAllowed\x12,\n" +
-	"\x11retryAfterSeconds\x18\x02 \x01(\x04R\x11retryAfterSeconds2U\n" +
-	"\vRateLimiter\x12F\n" +
-	"\x0fGetAccessStatus\x12\x17.GetAccessStatusRequest\x1a\x18.GetAccessStatusResponse\"\x00B\x15Z\x13rate-limiter-go/apib\x06proto3"
+func (x *GetAccessStatusResponse) GetDeniedLimit() string {
+	if x != nil {
+		return x.DeniedLimit
+	}
+	return ""
+}
+
+func (x *GetAccessStatusResponse) GetDeniedLevel() string {
+	if x != nil {
+		return x.DeniedLevel
+	}
+	return ""
+}
+
+func (x *GetAccessStatusResponse) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *GetAccessStatusResponse) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetAccessStatusResponse) GetResetAfterSeconds() uint64 {
+	if x != nil {
+		return x.ResetAfterSeconds
+	}
+	return 0
+}
+
+func (x *GetAccessStatusResponse) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *GetAccessStatusResponse) GetRetryAfterMilliseconds() uint64 {
+	if x != nil {
+		return x.RetryAfterMilliseconds
+	}
+	return 0
+}
+
+type CheckAccessStatusResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	IsAllowed              bool                   `protobuf:"varint,1,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
+	RetryAfterSeconds      uint64                 `protobuf:"varint,2,opt,name=retryAfterSeconds,proto3" json:"retryAfterSeconds,omitempty"`
+	Remaining              uint64                 `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Limit                  uint64                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	ResetAfterSeconds      uint64                 `protobuf:"varint,5,opt,name=resetAfterSeconds,proto3" json:"resetAfterSeconds,omitempty"`
+	DeniedLimit            string                 `protobuf:"bytes,6,opt,name=deniedLimit,proto3" json:"deniedLimit,omitempty"`
+	DeniedLevel            string                 `protobuf:"bytes,7,opt,name=deniedLevel,proto3" json:"deniedLevel,omitempty"`
+	RuleID                 string                 `protobuf:"bytes,8,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	RetryAfterMilliseconds uint64                 `protobuf:"varint,9,opt,name=retryAfterMilliseconds,proto3" json:"retryAfterMilliseconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CheckAccessStatusResponse) Reset() {
+	*x = CheckAccessStatusResponse{}
+	mi := &file_api_main_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckAccessStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckAccessStatusResponse) ProtoMessage() {}
+
+func (x *CheckAccessStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckAccessStatusResponse.ProtoReflect.Descriptor instead.
+func (*CheckAccessStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CheckAccessStatusResponse) GetIsAllowed() bool {
+	if x != nil {
+		return x.IsAllowed
+	}
+	return false
+}
+
+func (x *CheckAccessStatusResponse) GetRetryAfterSeconds() uint64 {
+	if x != nil {
+		return x.RetryAfterSeconds
+	}
+	return 0
+}
+
+func (x *CheckAccessStatusResponse) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *CheckAccessStatusResponse) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *CheckAccessStatusResponse) GetResetAfterSeconds() uint64 {
+	if x != nil {
+		return x.ResetAfterSeconds
+	}
+	return 0
+}
+
+func (x *CheckAccessStatusResponse) GetDeniedLimit() string {
+	if x != nil {
+		return x.DeniedLimit
+	}
+	return ""
+}
+
+func (x *CheckAccessStatusResponse) GetDeniedLevel() string {
+	if x != nil {
+		return x.DeniedLevel
+	}
+	return ""
+}
+
+func (x *CheckAccessStatusResponse) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *CheckAccessStatusResponse) GetRetryAfterMilliseconds() uint64 {
+	if x != nil {
+		return x.RetryAfterMilliseconds
+	}
+	return 0
+}
+
+type AcquireRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientID      string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ServiceID     string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UserID        string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	UserTier      string                 `protobuf:"bytes,4,opt,name=userTier,proto3" json:"userTier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcquireRequest) Reset() {
+	*x = AcquireRequest{}
+	mi := &file_api_main_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcquireRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcquireRequest) ProtoMessage() {}
+
+func (x *AcquireRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcquireRequest.ProtoReflect.Descriptor instead.
+func (*AcquireRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AcquireRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *AcquireRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *AcquireRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *AcquireRequest) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+type AcquireResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	IsAllowed          bool                   `protobuf:"varint,1,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
+	LeaseID            string                 `protobuf:"bytes,2,opt,name=leaseID,proto3" json:"leaseID,omitempty"`
+	LeaseExpiresAtUnix int64                  `protobuf:"varint,3,opt,name=leaseExpiresAtUnix,proto3" json:"leaseExpiresAtUnix,omitempty"`
+	RetryAfterSeconds  uint64                 `protobuf:"varint,4,opt,name=retryAfterSeconds,proto3" json:"retryAfterSeconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *AcquireResponse) Reset() {
+	*x = AcquireResponse{}
+	mi := &file_api_main_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcquireResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcquireResponse) ProtoMessage() {}
+
+func (x *AcquireResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcquireResponse.ProtoReflect.Descriptor instead.
+func (*AcquireResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AcquireResponse) GetIsAllowed() bool {
+	if x != nil {
+		return x.IsAllowed
+	}
+	return false
+}
+
+func (x *AcquireResponse) GetLeaseID() string {
+	if x != nil {
+		return x.LeaseID
+	}
+	return ""
+}
+
+func (x *AcquireResponse) GetLeaseExpiresAtUnix() int64 {
+	if x != nil {
+		return x.LeaseExpiresAtUnix
+	}
+	return 0
+}
+
+func (x *AcquireResponse) GetRetryAfterSeconds() uint64 {
+	if x != nil {
+		return x.RetryAfterSeconds
+	}
+	return 0
+}
+
+type ReleaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientID      string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ServiceID     string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UserID        string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	LeaseID       string                 `protobuf:"bytes,4,opt,name=leaseID,proto3" json:"leaseID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseRequest) Reset() {
+	*x = ReleaseRequest{}
+	mi := &file_api_main_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRequest) ProtoMessage() {}
+
+func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReleaseRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetLeaseID() string {
+	if x != nil {
+		return x.LeaseID
+	}
+	return ""
+}
+
+type ReleaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Released      bool                   `protobuf:"varint,1,opt,name=released,proto3" json:"released,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseResponse) Reset() {
+	*x = ReleaseResponse{}
+	mi := &file_api_main_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseResponse) ProtoMessage() {}
+
+func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReleaseResponse) GetReleased() bool {
+	if x != nil {
+		return x.Released
+	}
+	return false
+}
+
+type RefundRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ClientID       string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ServiceID      string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UserID         string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	UserTier       string                 `protobuf:"bytes,4,opt,name=userTier,proto3" json:"userTier,omitempty"`
+	UsageAmountReq uint64                 `protobuf:"varint,5,opt,name=usageAmountReq,proto3" json:"usageAmountReq,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefundRequest) Reset() {
+	*x = RefundRequest{}
+	mi := &file_api_main_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundRequest) ProtoMessage() {}
+
+func (x *RefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundRequest.ProtoReflect.Descriptor instead.
+func (*RefundRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RefundRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetUsageAmountReq() uint64 {
+	if x != nil {
+		return x.UsageAmountReq
+	}
+	return 0
+}
+
+func (x *RefundRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RefundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Refunded      bool                   `protobuf:"varint,1,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	Remaining     uint64                 `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Limit         uint64                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundResponse) Reset() {
+	*x = RefundResponse{}
+	mi := &file_api_main_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundResponse) ProtoMessage() {}
+
+func (x *RefundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundResponse.ProtoReflect.Descriptor instead.
+func (*RefundResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RefundResponse) GetRefunded() bool {
+	if x != nil {
+		return x.Refunded
+	}
+	return false
+}
+
+func (x *RefundResponse) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ReserveRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ClientID       string                 `protobuf:"bytes,1,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	ServiceID      string                 `protobuf:"bytes,2,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UserID         string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	UserTier       string                 `protobuf:"bytes,4,opt,name=userTier,proto3" json:"userTier,omitempty"`
+	UsageAmountReq uint64                 `protobuf:"varint,5,opt,name=usageAmountReq,proto3" json:"usageAmountReq,omitempty"`
+	TtlSeconds     uint64                 `protobuf:"varint,6,opt,name=ttlSeconds,proto3" json:"ttlSeconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReserveRequest) Reset() {
+	*x = ReserveRequest{}
+	mi := &file_api_main_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRequest) ProtoMessage() {}
+
+func (x *ReserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRequest.ProtoReflect.Descriptor instead.
+func (*ReserveRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReserveRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetUsageAmountReq() uint64 {
+	if x != nil {
+		return x.UsageAmountReq
+	}
+	return 0
+}
+
+func (x *ReserveRequest) GetTtlSeconds() uint64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type ReserveResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IsAllowed         bool                   `protobuf:"varint,1,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
+	RetryAfterSeconds uint64                 `protobuf:"varint,2,opt,name=retryAfterSeconds,proto3" json:"retryAfterSeconds,omitempty"`
+	ReservationID     string                 `protobuf:"bytes,3,opt,name=reservationID,proto3" json:"reservationID,omitempty"`
+	ExpiresAtUnix     int64                  `protobuf:"varint,4,opt,name=expiresAtUnix,proto3" json:"expiresAtUnix,omitempty"`
+	DeniedLimit       string                 `protobuf:"bytes,5,opt,name=deniedLimit,proto3" json:"deniedLimit,omitempty"`
+	DeniedLevel       string                 `protobuf:"bytes,6,opt,name=deniedLevel,proto3" json:"deniedLevel,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ReserveResponse) Reset() {
+	*x = ReserveResponse{}
+	mi := &file_api_main_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveResponse) ProtoMessage() {}
+
+func (x *ReserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveResponse.ProtoReflect.Descriptor instead.
+func (*ReserveResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReserveResponse) GetIsAllowed() bool {
+	if x != nil {
+		return x.IsAllowed
+	}
+	return false
+}
+
+func (x *ReserveResponse) GetRetryAfterSeconds() uint64 {
+	if x != nil {
+		return x.RetryAfterSeconds
+	}
+	return 0
+}
+
+func (x *ReserveResponse) GetReservationID() string {
+	if x != nil {
+		return x.ReservationID
+	}
+	return ""
+}
+
+func (x *ReserveResponse) GetExpiresAtUnix() int64 {
+	if x != nil {
+		return x.ExpiresAtUnix
+	}
+	return 0
+}
+
+func (x *ReserveResponse) GetDeniedLimit() string {
+	if x != nil {
+		return x.DeniedLimit
+	}
+	return ""
+}
+
+func (x *ReserveResponse) GetDeniedLevel() string {
+	if x != nil {
+		return x.DeniedLevel
+	}
+	return ""
+}
+
+type CommitRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReservationID  string                 `protobuf:"bytes,1,opt,name=reservationID,proto3" json:"reservationID,omitempty"`
+	UsageAmountReq uint64                 `protobuf:"varint,2,opt,name=usageAmountReq,proto3" json:"usageAmountReq,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CommitRequest) Reset() {
+	*x = CommitRequest{}
+	mi := &file_api_main_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRequest) ProtoMessage() {}
+
+func (x *CommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRequest.ProtoReflect.Descriptor instead.
+func (*CommitRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CommitRequest) GetReservationID() string {
+	if x != nil {
+		return x.ReservationID
+	}
+	return ""
+}
+
+func (x *CommitRequest) GetUsageAmountReq() uint64 {
+	if x != nil {
+		return x.UsageAmountReq
+	}
+	return 0
+}
+
+type CommitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Remaining     uint64                 `protobuf:"varint,1,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Limit         uint64                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitResponse) Reset() {
+	*x = CommitResponse{}
+	mi := &file_api_main_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitResponse) ProtoMessage() {}
+
+func (x *CommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitResponse.ProtoReflect.Descriptor instead.
+func (*CommitResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CommitResponse) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *CommitResponse) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationID string                 `protobuf:"bytes,1,opt,name=reservationID,proto3" json:"reservationID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_api_main_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelRequest) GetReservationID() string {
+	if x != nil {
+		return x.ReservationID
+	}
+	return ""
+}
+
+type CancelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelResponse) Reset() {
+	*x = CancelResponse{}
+	mi := &file_api_main_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelResponse) ProtoMessage() {}
+
+func (x *CancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CancelResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+type BatchGetAccessStatusRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Requests      []*GetAccessStatusRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	AllOrNothing  bool                      `protobuf:"varint,2,opt,name=allOrNothing,proto3" json:"allOrNothing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetAccessStatusRequest) Reset() {
+	*x = BatchGetAccessStatusRequest{}
+	mi := &file_api_main_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetAccessStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetAccessStatusRequest) ProtoMessage() {}
+
+func (x *BatchGetAccessStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetAccessStatusRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetAccessStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchGetAccessStatusRequest) GetRequests() []*GetAccessStatusRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *BatchGetAccessStatusRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+type BatchAccessStatusResult struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Status        *GetAccessStatusResponse `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAccessStatusResult) Reset() {
+	*x = BatchAccessStatusResult{}
+	mi := &file_api_main_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAccessStatusResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAccessStatusResult) ProtoMessage() {}
+
+func (x *BatchAccessStatusResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAccessStatusResult.ProtoReflect.Descriptor instead.
+func (*BatchAccessStatusResult) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchAccessStatusResult) GetStatus() *GetAccessStatusResponse {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *BatchAccessStatusResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchGetAccessStatusResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Results       []*BatchAccessStatusResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	IsAllowed     bool                       `protobuf:"varint,2,opt,name=isAllowed,proto3" json:"isAllowed,omitempty"`
+	BatchStatus   *GetAccessStatusResponse   `protobuf:"bytes,3,opt,name=batchStatus,proto3" json:"batchStatus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetAccessStatusResponse) Reset() {
+	*x = BatchGetAccessStatusResponse{}
+	mi := &file_api_main_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetAccessStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetAccessStatusResponse) ProtoMessage() {}
+
+func (x *BatchGetAccessStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetAccessStatusResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetAccessStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchGetAccessStatusResponse) GetResults() []*BatchAccessStatusResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchGetAccessStatusResponse) GetIsAllowed() bool {
+	if x != nil {
+		return x.IsAllowed
+	}
+	return false
+}
+
+func (x *BatchGetAccessStatusResponse) GetBatchStatus() *GetAccessStatusResponse {
+	if x != nil {
+		return x.BatchStatus
+	}
+	return nil
+}
+
+type StreamAccessStatusRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	RequestID     string                  `protobuf:"bytes,1,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Request       *GetAccessStatusRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAccessStatusRequest) Reset() {
+	*x = StreamAccessStatusRequest{}
+	mi := &file_api_main_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAccessStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAccessStatusRequest) ProtoMessage() {}
+
+func (x *StreamAccessStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAccessStatusRequest.ProtoReflect.Descriptor instead.
+func (*StreamAccessStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StreamAccessStatusRequest) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *StreamAccessStatusRequest) GetRequest() *GetAccessStatusRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type StreamAccessStatusResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	RequestID     string                   `protobuf:"bytes,1,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Status        *GetAccessStatusResponse `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAccessStatusResponse) Reset() {
+	*x = StreamAccessStatusResponse{}
+	mi := &file_api_main_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAccessStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAccessStatusResponse) ProtoMessage() {}
+
+func (x *StreamAccessStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAccessStatusResponse.ProtoReflect.Descriptor instead.
+func (*StreamAccessStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StreamAccessStatusResponse) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *StreamAccessStatusResponse) GetStatus() *GetAccessStatusResponse {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *StreamAccessStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Service struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID          string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UsagePriceInTokens uint64                 `protobuf:"varint,2,opt,name=usagePriceInTokens,proto3" json:"usagePriceInTokens,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	mi := &file_api_main_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Service) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *Service) GetUsagePriceInTokens() uint64 {
+	if x != nil {
+		return x.UsagePriceInTokens
+	}
+	return 0
+}
+
+type CreateServiceRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID          string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UsagePriceInTokens uint64                 `protobuf:"varint,2,opt,name=usagePriceInTokens,proto3" json:"usagePriceInTokens,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateServiceRequest) Reset() {
+	*x = CreateServiceRequest{}
+	mi := &file_api_main_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceRequest) ProtoMessage() {}
+
+func (x *CreateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreateServiceRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *CreateServiceRequest) GetUsagePriceInTokens() uint64 {
+	if x != nil {
+		return x.UsagePriceInTokens
+	}
+	return 0
+}
+
+type UpdateServiceRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID          string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	UsagePriceInTokens uint64                 `protobuf:"varint,2,opt,name=usagePriceInTokens,proto3" json:"usagePriceInTokens,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateServiceRequest) Reset() {
+	*x = UpdateServiceRequest{}
+	mi := &file_api_main_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceRequest) ProtoMessage() {}
+
+func (x *UpdateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateServiceRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *UpdateServiceRequest) GetUsagePriceInTokens() uint64 {
+	if x != nil {
+		return x.UsagePriceInTokens
+	}
+	return 0
+}
+
+type GetServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID     string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceRequest) Reset() {
+	*x = GetServiceRequest{}
+	mi := &file_api_main_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceRequest) ProtoMessage() {}
+
+func (x *GetServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServiceRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetServiceRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+type ListServicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesRequest) Reset() {
+	*x = ListServicesRequest{}
+	mi := &file_api_main_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesRequest) ProtoMessage() {}
+
+func (x *ListServicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicesRequest.ProtoReflect.Descriptor instead.
+func (*ListServicesRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{24}
+}
+
+type ListServicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*Service             `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesResponse) Reset() {
+	*x = ListServicesResponse{}
+	mi := &file_api_main_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesResponse) ProtoMessage() {}
+
+func (x *ListServicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicesResponse.ProtoReflect.Descriptor instead.
+func (*ListServicesResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListServicesResponse) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+type DeleteServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID     string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceRequest) Reset() {
+	*x = DeleteServiceRequest{}
+	mi := &file_api_main_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceRequest) ProtoMessage() {}
+
+func (x *DeleteServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteServiceRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+type DeleteServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceResponse) Reset() {
+	*x = DeleteServiceResponse{}
+	mi := &file_api_main_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceResponse) ProtoMessage() {}
+
+func (x *DeleteServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteServiceResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type Bucket struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	BucketID          string                 `protobuf:"bytes,1,opt,name=bucketID,proto3" json:"bucketID,omitempty"`
+	Key               string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Level             string                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`
+	LimitName         string                 `protobuf:"bytes,4,opt,name=limitName,proto3" json:"limitName,omitempty"`
+	RuleID            string                 `protobuf:"bytes,5,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	ServiceID         string                 `protobuf:"bytes,6,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	ClientID          string                 `protobuf:"bytes,7,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	Algorithm         string                 `protobuf:"bytes,8,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Remaining         uint64                 `protobuf:"varint,9,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Limit             uint64                 `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	ResetAfterSeconds uint64                 `protobuf:"varint,11,opt,name=resetAfterSeconds,proto3" json:"resetAfterSeconds,omitempty"`
+	CreatedAtUnix     int64                  `protobuf:"varint,12,opt,name=createdAtUnix,proto3" json:"createdAtUnix,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_api_main_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Bucket) GetBucketID() string {
+	if x != nil {
+		return x.BucketID
+	}
+	return ""
+}
+
+func (x *Bucket) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Bucket) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Bucket) GetLimitName() string {
+	if x != nil {
+		return x.LimitName
+	}
+	return ""
+}
+
+func (x *Bucket) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *Bucket) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *Bucket) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *Bucket) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *Bucket) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *Bucket) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Bucket) GetResetAfterSeconds() uint64 {
+	if x != nil {
+		return x.ResetAfterSeconds
+	}
+	return 0
+}
+
+func (x *Bucket) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+type GetBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketID      string                 `protobuf:"bytes,1,opt,name=bucketID,proto3" json:"bucketID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBucketRequest) Reset() {
+	*x = GetBucketRequest{}
+	mi := &file_api_main_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBucketRequest) ProtoMessage() {}
+
+func (x *GetBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBucketRequest.ProtoReflect.Descriptor instead.
+func (*GetBucketRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetBucketRequest) GetBucketID() string {
+	if x != nil {
+		return x.BucketID
+	}
+	return ""
+}
+
+type ListBucketsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceID     string                 `protobuf:"bytes,1,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	ClientID      string                 `protobuf:"bytes,2,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsRequest) Reset() {
+	*x = ListBucketsRequest{}
+	mi := &file_api_main_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsRequest) ProtoMessage() {}
+
+func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsRequest.ProtoReflect.Descriptor instead.
+func (*ListBucketsRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListBucketsRequest) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *ListBucketsRequest) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *ListBucketsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListBucketsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListBucketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*Bucket              `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsResponse) Reset() {
+	*x = ListBucketsResponse{}
+	mi := &file_api_main_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsResponse) ProtoMessage() {}
+
+func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsResponse.ProtoReflect.Descriptor instead.
+func (*ListBucketsResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListBucketsResponse) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *ListBucketsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ResetBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketID      string                 `protobuf:"bytes,1,opt,name=bucketID,proto3" json:"bucketID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetBucketRequest) Reset() {
+	*x = ResetBucketRequest{}
+	mi := &file_api_main_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetBucketRequest) ProtoMessage() {}
+
+func (x *ResetBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetBucketRequest.ProtoReflect.Descriptor instead.
+func (*ResetBucketRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ResetBucketRequest) GetBucketID() string {
+	if x != nil {
+		return x.BucketID
+	}
+	return ""
+}
+
+type SetBucketTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketID      string                 `protobuf:"bytes,1,opt,name=bucketID,proto3" json:"bucketID,omitempty"`
+	Tokens        uint64                 `protobuf:"varint,2,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBucketTokensRequest) Reset() {
+	*x = SetBucketTokensRequest{}
+	mi := &file_api_main_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBucketTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBucketTokensRequest) ProtoMessage() {}
+
+func (x *SetBucketTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBucketTokensRequest.ProtoReflect.Descriptor instead.
+func (*SetBucketTokensRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetBucketTokensRequest) GetBucketID() string {
+	if x != nil {
+		return x.BucketID
+	}
+	return ""
+}
+
+func (x *SetBucketTokensRequest) GetTokens() uint64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+type DeleteBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BucketID      string                 `protobuf:"bytes,1,opt,name=bucketID,proto3" json:"bucketID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBucketRequest) Reset() {
+	*x = DeleteBucketRequest{}
+	mi := &file_api_main_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBucketRequest) ProtoMessage() {}
+
+func (x *DeleteBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBucketRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBucketRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeleteBucketRequest) GetBucketID() string {
+	if x != nil {
+		return x.BucketID
+	}
+	return ""
+}
+
+type DeleteBucketResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBucketResponse) Reset() {
+	*x = DeleteBucketResponse{}
+	mi := &file_api_main_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBucketResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBucketResponse) ProtoMessage() {}
+
+func (x *DeleteBucketResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBucketResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBucketResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DeleteBucketResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type RuleLimit struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Algorithm           string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	RefillRatePerSecond uint64                 `protobuf:"varint,3,opt,name=refillRatePerSecond,proto3" json:"refillRatePerSecond,omitempty"`
+	InitialTokens       uint64                 `protobuf:"varint,4,opt,name=initialTokens,proto3" json:"initialTokens,omitempty"`
+	MaxTokens           uint64                 `protobuf:"varint,5,opt,name=maxTokens,proto3" json:"maxTokens,omitempty"`
+	WindowSeconds       uint64                 `protobuf:"varint,6,opt,name=windowSeconds,proto3" json:"windowSeconds,omitempty"`
+	Period              string                 `protobuf:"bytes,7,opt,name=period,proto3" json:"period,omitempty"`
+	TimeZone            string                 `protobuf:"bytes,8,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RuleLimit) Reset() {
+	*x = RuleLimit{}
+	mi := &file_api_main_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleLimit) ProtoMessage() {}
+
+func (x *RuleLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleLimit.ProtoReflect.Descriptor instead.
+func (*RuleLimit) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RuleLimit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleLimit) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *RuleLimit) GetRefillRatePerSecond() uint64 {
+	if x != nil {
+		return x.RefillRatePerSecond
+	}
+	return 0
+}
+
+func (x *RuleLimit) GetInitialTokens() uint64 {
+	if x != nil {
+		return x.InitialTokens
+	}
+	return 0
+}
+
+func (x *RuleLimit) GetMaxTokens() uint64 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
+func (x *RuleLimit) GetWindowSeconds() uint64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *RuleLimit) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *RuleLimit) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type Rule struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RuleID              string                 `protobuf:"bytes,1,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	Level               string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	ServiceID           string                 `protobuf:"bytes,3,opt,name=serviceID,proto3" json:"serviceID,omitempty"`
+	ClientID            string                 `protobuf:"bytes,4,opt,name=clientID,proto3" json:"clientID,omitempty"`
+	OrgID               string                 `protobuf:"bytes,5,opt,name=orgID,proto3" json:"orgID,omitempty"`
+	UserID              string                 `protobuf:"bytes,6,opt,name=userID,proto3" json:"userID,omitempty"`
+	UserTier            string                 `protobuf:"bytes,7,opt,name=userTier,proto3" json:"userTier,omitempty"`
+	Algorithm           string                 `protobuf:"bytes,8,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	RefillRatePerSecond uint64                 `protobuf:"varint,9,opt,name=refillRatePerSecond,proto3" json:"refillRatePerSecond,omitempty"`
+	InitialTokens       uint64                 `protobuf:"varint,10,opt,name=initialTokens,proto3" json:"initialTokens,omitempty"`
+	MaxTokens           uint64                 `protobuf:"varint,11,opt,name=maxTokens,proto3" json:"maxTokens,omitempty"`
+	WindowSeconds       uint64                 `protobuf:"varint,12,opt,name=windowSeconds,proto3" json:"windowSeconds,omitempty"`
+	Period              string                 `protobuf:"bytes,13,opt,name=period,proto3" json:"period,omitempty"`
+	TimeZone            string                 `protobuf:"bytes,14,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	MaxConcurrent       uint64                 `protobuf:"varint,15,opt,name=maxConcurrent,proto3" json:"maxConcurrent,omitempty"`
+	LeaseTTLSeconds     uint64                 `protobuf:"varint,16,opt,name=leaseTTLSeconds,proto3" json:"leaseTTLSeconds,omitempty"`
+	Limits              []*RuleLimit           `protobuf:"bytes,17,rep,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_api_main_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Rule) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *Rule) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Rule) GetServiceID() string {
+	if x != nil {
+		return x.ServiceID
+	}
+	return ""
+}
+
+func (x *Rule) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *Rule) GetOrgID() string {
+	if x != nil {
+		return x.OrgID
+	}
+	return ""
+}
+
+func (x *Rule) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *Rule) GetUserTier() string {
+	if x != nil {
+		return x.UserTier
+	}
+	return ""
+}
+
+func (x *Rule) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *Rule) GetRefillRatePerSecond() uint64 {
+	if x != nil {
+		return x.RefillRatePerSecond
+	}
+	return 0
+}
+
+func (x *Rule) GetInitialTokens() uint64 {
+	if x != nil {
+		return x.InitialTokens
+	}
+	return 0
+}
+
+func (x *Rule) GetMaxTokens() uint64 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
+func (x *Rule) GetWindowSeconds() uint64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *Rule) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *Rule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Rule) GetMaxConcurrent() uint64 {
+	if x != nil {
+		return x.MaxConcurrent
+	}
+	return 0
+}
+
+func (x *Rule) GetLeaseTTLSeconds() uint64 {
+	if x != nil {
+		return x.LeaseTTLSeconds
+	}
+	return 0
+}
+
+func (x *Rule) GetLimits() []*RuleLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type CreateRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *Rule                  `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRuleRequest) Reset() {
+	*x = CreateRuleRequest{}
+	mi := &file_api_main_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRuleRequest) ProtoMessage() {}
+
+func (x *CreateRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateRuleRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CreateRuleRequest) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type UpdateRuleRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Rule           *Rule                  `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	MigrateBuckets bool                   `protobuf:"varint,2,opt,name=migrateBuckets,proto3" json:"migrateBuckets,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateRuleRequest) Reset() {
+	*x = UpdateRuleRequest{}
+	mi := &file_api_main_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRuleRequest) ProtoMessage() {}
+
+func (x *UpdateRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRuleRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UpdateRuleRequest) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *UpdateRuleRequest) GetMigrateBuckets() bool {
+	if x != nil {
+		return x.MigrateBuckets
+	}
+	return false
+}
+
+type DeleteRuleRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RuleID         string                 `protobuf:"bytes,1,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	MigrateBuckets bool                   `protobuf:"varint,2,opt,name=migrateBuckets,proto3" json:"migrateBuckets,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteRuleRequest) Reset() {
+	*x = DeleteRuleRequest{}
+	mi := &file_api_main_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRuleRequest) ProtoMessage() {}
+
+func (x *DeleteRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRuleRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteRuleRequest) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *DeleteRuleRequest) GetMigrateBuckets() bool {
+	if x != nil {
+		return x.MigrateBuckets
+	}
+	return false
+}
+
+type GetRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleID        string                 `protobuf:"bytes,1,opt,name=ruleID,proto3" json:"ruleID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleRequest) Reset() {
+	*x = GetRuleRequest{}
+	mi := &file_api_main_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleRequest) ProtoMessage() {}
+
+func (x *GetRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleRequest.ProtoReflect.Descriptor instead.
+func (*GetRuleRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetRuleRequest) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+type ListRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRulesRequest) Reset() {
+	*x = ListRulesRequest{}
+	mi := &file_api_main_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRulesRequest) ProtoMessage() {}
+
+func (x *ListRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRulesRequest) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{42}
+}
+
+type ListRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*Rule                `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRulesResponse) Reset() {
+	*x = ListRulesResponse{}
+	mi := &file_api_main_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRulesResponse) ProtoMessage() {}
+
+func (x *ListRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRulesResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListRulesResponse) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ListRulesResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RuleChangeResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Rule            *Rule                  `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Version         uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	MigratedBuckets uint64                 `protobuf:"varint,3,opt,name=migratedBuckets,proto3" json:"migratedBuckets,omitempty"`
+	DeletedBuckets  uint64                 `protobuf:"varint,4,opt,name=deletedBuckets,proto3" json:"deletedBuckets,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RuleChangeResponse) Reset() {
+	*x = RuleChangeResponse{}
+	mi := &file_api_main_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleChangeResponse) ProtoMessage() {}
+
+func (x *RuleChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_main_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleChangeResponse.ProtoReflect.Descriptor instead.
+func (*RuleChangeResponse) Descriptor() ([]byte, []int) {
+	return file_api_main_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RuleChangeResponse) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *RuleChangeResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RuleChangeResponse) GetMigratedBuckets() uint64 {
+	if x != nil {
+		return x.MigratedBuckets
+	}
+	return 0
+}
+
+func (x *RuleChangeResponse) GetDeletedBuckets() uint64 {
+	if x != nil {
+		return x.DeletedBuckets
+	}
+	return 0
+}
+
+var File_api_main_proto protoreflect.FileDescriptor
+
+const file_api_main_proto_rawDesc = "" +
+	"\n" +
+	"\x0eapi/main.proto\"\xd8\x01\n" +
+	"\x16GetAccessStatusRequest\x12\x1a\n" +
+	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
+	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12&\n" +
+	"\x0eusageAmountReq\x18\x04 \x01(\x04R\x0eusageAmountReq\x12\x1a\n" +
+	"\buserTier\x18\x05 \x01(\tR\buserTier\x12(\n" +
+	"\x0fwaitForCapacity\x18\x06 \x01(\bR\x0fwaitForCapacity\"\x89\x03\n" +
+	"\x17GetAccessStatusResponse\x12\x1c\n" +
+	"\tisAllowed\x18\x01 \x01(\bR\tisAllowed\x12,\n" +
+	"\x11retryAfterSeconds\x18\x02 \x01(\x04R\x11retryAfterSeconds\x12,\n" +
+	"\x11delayMilliseconds\x18\x03 \x01(\x04R\x11delayMilliseconds\x12 \n" +
+	"\vdeniedLimit\x18\x04 \x01(\tR\vdeniedLimit\x12 \n" +
+	"\vdeniedLevel\x18\x05 \x01(\tR\vdeniedLevel\x12\x1c\n" +
+	"\tremaining\x18\x06 \x01(\x04R\tremaining\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x04R\x05limit\x12,\n" +
+	"\x11resetAfterSeconds\x18\b \x01(\x04R\x11resetAfterSeconds\x12\x16\n" +
+	"\x06ruleID\x18\t \x01(\tR\x06ruleID\x126\n" +
+	"\x16retryAfterMilliseconds\x18\n" +
+	" \x01(\x04R\x16retryAfterMilliseconds\"\xdd\x02\n" +
+	"\x19CheckAccessStatusResponse\x12\x1c\n" +
+	"\tisAllowed\x18\x01 \x01(\bR\tisAllowed\x12,\n" +
+	"\x11retryAfterSeconds\x18\x02 \x01(\x04R\x11retryAfterSeconds\x12\x1c\n" +
+	"\tremaining\x18\x03 \x01(\x04R\tremaining\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x04R\x05limit\x12,\n" +
+	"\x11resetAfterSeconds\x18\x05 \x01(\x04R\x11resetAfterSeconds\x12 \n" +
+	"\vdeniedLimit\x18\x06 \x01(\tR\vdeniedLimit\x12 \n" +
+	"\vdeniedLevel\x18\a \x01(\tR\vdeniedLevel\x12\x16\n" +
+	"\x06ruleID\x18\b \x01(\tR\x06ruleID\x126\n" +
+	"\x16retryAfterMilliseconds\x18\t \x01(\x04R\x16retryAfterMilliseconds\"~\n" +
+	"\x0eAcquireRequest\x12\x1a\n" +
+	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
+	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12\x1a\n" +
+	"\buserTier\x18\x04 \x01(\tR\buserTier\"\xa7\x01\n" +
+	"\x0fAcquireResponse\x12\x1c\n" +
+	"\tisAllowed\x18\x01 \x01(\bR\tisAllowed\x12\x18\n" +
+	"\aleaseID\x18\x02 \x01(\tR\aleaseID\x12.\n" +
+	"\x12leaseExpiresAtUnix\x18\x03 \x01(\x03R\x12leaseExpiresAtUnix\x12,\n" +
+	"\x11retryAfterSeconds\x18\x04 \x01(\x04R\x11retryAfterSeconds\"|\n" +
+	"\x0eReleaseRequest\x12\x1a\n" +
+	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
+	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12\x18\n" +
+	"\aleaseID\x18\x04 \x01(\tR\aleaseID\"-\n" +
+	"\x0fReleaseResponse\x12\x1a\n" +
+	"\breleased\x18\x01 \x01(\bR\breleased\"\xcd\x01\n" +
+	"\rRefundRequest\x12\x1a\n" +
+	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
+	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12\x1a\n" +
+	"\buserTier\x18\x04 \x01(\tR\buserTier\x12&\n" +
+	"\x0eusageAmountReq\x18\x05 \x01(\x04R\x0eusageAmountReq\x12&\n" +
+	"\x0eidempotencyKey\x18\x06 \x01(\tR\x0eidempotencyKey\"`\n" +
+	"\x0eRefundResponse\x12\x1a\n" +
+	"\brefunded\x18\x01 \x01(\bR\brefunded\x12\x1c\n" +
+	"\tremaining\x18\x02 \x01(\x04R\tremaining\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x04R\x05limit\"\xc6\x01\n" +
+	"\x0eReserveRequest\x12\x1a\n" +
+	"\bclientID\x18\x01 \x01(\tR\bclientID\x12\x1c\n" +
+	"\tserviceID\x18\x02 \x01(\tR\tserviceID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12\x1a\n" +
+	"\buserTier\x18\x04 \x01(\tR\buserTier\x12&\n" +
+	"\x0eusageAmountReq\x18\x05 \x01(\x04R\x0eusageAmountReq\x12\x1e\n" +
+	"\n" +
+	"ttlSeconds\x18\x06 \x01(\x04R\n" +
+	"ttlSeconds\"\xed\x01\n" +
+	"\x0fReserveResponse\x12\x1c\n" +
+	"\tisAllowed\x18\x01 \x01(\bR\tisAllowed\x12,\n" +
+	"\x11retryAfterSeconds\x18\x02 \x01(\x04R\x11retryAfterSeconds\x12$\n" +
+	"\rreservationID\x18\x03 \x01(\tR\rreservationID\x12$\n" +
+	"\rexpiresAtUnix\x18\x04 \x01(\x03R\rexpiresAtUnix\x12 \n" +
+	"\vdeniedLimit\x18\x05 \x01(\tR\vdeniedLimit\x12 \n" +
+	"\vdeniedLevel\x18\x06 \x01(\tR\vdeniedLevel\"]\n" +
+	"\rCommitRequest\x12$\n" +
+	"\rreservationID\x18\x01 \x01(\tR\rreservationID\x12&\n" +
+	"\x0eusageAmountReq\x18\x02 \x01(\x04R\x0eusageAmountReq\"D\n" +
+	"\x0eCommitResponse\x12\x1c\n" +
+	"\tremaining\x18\x01 \x01(\x04R\tremaining\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x04R\x05limit\"5\n" +
+	"\rCancelRequest\x12$\n" +
+	"\rreservationID\x18\x01 \x01(\tR\rreservationID\".\n" +
+	"\x0eCancelResponse\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\"v\n" +
+	"\x1bBatchGetAccessStatusRequest\x123\n" +
+	"\brequests\x18\x01 \x03(\v2\x17.GetAccessStatusRequestR\brequests\x12\"\n" +
+	"\fallOrNothing\x18\x02 \x01(\bR\fallOrNothing\"a\n" +
+	"\x17BatchAccessStatusResult\x120\n" +
+	"\x06status\x18\x01 \x01(\v2\x18.GetAccessStatusResponseR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xac\x01\n" +
+	"\x1cBatchGetAccessStatusResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.BatchAccessStatusResultR\aresults\x12\x1c\n" +
+	"\tisAllowed\x18\x02 \x01(\bR\tisAllowed\x12:\n" +
+	"\vbatchStatus\x18\x03 \x01(\v2\x18.GetAccessStatusResponseR\vbatchStatus\"l\n" +
+	"\x19StreamAccessStatusRequest\x12\x1c\n" +
+	"\trequestID\x18\x01 \x01(\tR\trequestID\x121\n" +
+	"\arequest\x18\x02 \x01(\v2\x17.GetAccessStatusRequestR\arequest\"\x82\x01\n" +
+	"\x1aStreamAccessStatusResponse\x12\x1c\n" +
+	"\trequestID\x18\x01 \x01(\tR\trequestID\x120\n" +
+	"\x06status\x18\x02 \x01(\v2\x18.GetAccessStatusResponseR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"W\n" +
+	"\aService\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\x12.\n" +
+	"\x12usagePriceInTokens\x18\x02 \x01(\x04R\x12usagePriceInTokens\"d\n" +
+	"\x14CreateServiceRequest\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\x12.\n" +
+	"\x12usagePriceInTokens\x18\x02 \x01(\x04R\x12usagePriceInTokens\"d\n" +
+	"\x14UpdateServiceRequest\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\x12.\n" +
+	"\x12usagePriceInTokens\x18\x02 \x01(\x04R\x12usagePriceInTokens\"1\n" +
+	"\x11GetServiceRequest\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\"\x15\n" +
+	"\x13ListServicesRequest\"<\n" +
+	"\x14ListServicesResponse\x12$\n" +
+	"\bservices\x18\x01 \x03(\v2\b.ServiceR\bservices\"4\n" +
+	"\x14DeleteServiceRequest\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\"1\n" +
+	"\x15DeleteServiceResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\xe2\x02\n" +
+	"\x06Bucket\x12\x1a\n" +
+	"\bbucketID\x18\x01 \x01(\tR\bbucketID\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\tR\x05level\x12\x1c\n" +
+	"\tlimitName\x18\x04 \x01(\tR\tlimitName\x12\x16\n" +
+	"\x06ruleID\x18\x05 \x01(\tR\x06ruleID\x12\x1c\n" +
+	"\tserviceID\x18\x06 \x01(\tR\tserviceID\x12\x1a\n" +
+	"\bclientID\x18\a \x01(\tR\bclientID\x12\x1c\n" +
+	"\talgorithm\x18\b \x01(\tR\talgorithm\x12\x1c\n" +
+	"\tremaining\x18\t \x01(\x04R\tremaining\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x04R\x05limit\x12,\n" +
+	"\x11resetAfterSeconds\x18\v \x01(\x04R\x11resetAfterSeconds\x12$\n" +
+	"\rcreatedAtUnix\x18\f \x01(\x03R\rcreatedAtUnix\".\n" +
+	"\x10GetBucketRequest\x12\x1a\n" +
+	"\bbucketID\x18\x01 \x01(\tR\bbucketID\"|\n" +
+	"\x12ListBucketsRequest\x12\x1c\n" +
+	"\tserviceID\x18\x01 \x01(\tR\tserviceID\x12\x1a\n" +
+	"\bclientID\x18\x02 \x01(\tR\bclientID\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\"X\n" +
+	"\x13ListBucketsResponse\x12!\n" +
+	"\abuckets\x18\x01 \x03(\v2\a.BucketR\abuckets\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"0\n" +
+	"\x12ResetBucketRequest\x12\x1a\n" +
+	"\bbucketID\x18\x01 \x01(\tR\bbucketID\"L\n" +
+	"\x16SetBucketTokensRequest\x12\x1a\n" +
+	"\bbucketID\x18\x01 \x01(\tR\bbucketID\x12\x16\n" +
+	"\x06tokens\x18\x02 \x01(\x04R\x06tokens\"1\n" +
+	"\x13DeleteBucketRequest\x12\x1a\n" +
+	"\bbucketID\x18\x01 \x01(\tR\bbucketID\"0\n" +
+	"\x14DeleteBucketResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\x8d\x02\n" +
+	"\tRuleLimit\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x120\n" +
+	"\x13refillRatePerSecond\x18\x03 \x01(\x04R\x13refillRatePerSecond\x12$\n" +
+	"\rinitialTokens\x18\x04 \x01(\x04R\rinitialTokens\x12\x1c\n" +
+	"\tmaxTokens\x18\x05 \x01(\x04R\tmaxTokens\x12$\n" +
+	"\rwindowSeconds\x18\x06 \x01(\x04R\rwindowSeconds\x12\x16\n" +
+	"\x06period\x18\a \x01(\tR\x06period\x12\x1a\n" +
+	"\btimeZone\x18\b \x01(\tR\btimeZone\"\x9a\x04\n" +
+	"\x04Rule\x12\x16\n" +
+	"\x06ruleID\x18\x01 \x01(\tR\x06ruleID\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1c\n" +
+	"\tserviceID\x18\x03 \x01(\tR\tserviceID\x12\x1a\n" +
+	"\bclientID\x18\x04 \x01(\tR\bclientID\x12\x14\n" +
+	"\x05orgID\x18\x05 \x01(\tR\x05orgID\x12\x16\n" +
+	"\x06userID\x18\x06 \x01(\tR\x06userID\x12\x1a\n" +
+	"\buserTier\x18\a \x01(\tR\buserTier\x12\x1c\n" +
+	"\talgorithm\x18\b \x01(\tR\talgorithm\x120\n" +
+	"\x13refillRatePerSecond\x18\t \x01(\x04R\x13refillRatePerSecond\x12$\n" +
+	"\rinitialTokens\x18\n" +
+	" \x01(\x04R\rinitialTokens\x12\x1c\n" +
+	"\tmaxTokens\x18\v \x01(\x04R\tmaxTokens\x12$\n" +
+	"\rwindowSeconds\x18\f \x01(\x04R\rwindowSeconds\x12\x16\n" +
+	"\x06period\x18\r \x01(\tR\x06period\x12\x1a\n" +
+	"\btimeZone\x18\x0e \x01(\tR\btimeZone\x12$\n" +
+	"\rmaxConcurrent\x18\x0f \x01(\x04R\rmaxConcurrent\x12(\n" +
+	"\x0fleaseTTLSeconds\x18\x10 \x01(\x04R\x0fleaseTTLSeconds\x12\"\n" +
+	"\x06limits\x18\x11 \x03(\v2\n" +
+	".RuleLimitR\x06limits\".\n" +
+	"\x11CreateRuleRequest\x12\x19\n" +
+	"\x04rule\x18\x01 \x01(\v2\x05.RuleR\x04rule\"V\n" +
+	"\x11UpdateRuleRequest\x12\x19\n" +
+	"\x04rule\x18\x01 \x01(\v2\x05.RuleR\x04rule\x12&\n" +
+	"\x0emigrateBuckets\x18\x02 \x01(\bR\x0emigrateBuckets\"S\n" +
+	"\x11DeleteRuleRequest\x12\x16\n" +
+	"\x06ruleID\x18\x01 \x01(\tR\x06ruleID\x12&\n" +
+	"\x0emigrateBuckets\x18\x02 \x01(\bR\x0emigrateBuckets\"(\n" +
+	"\x0eGetRuleRequest\x12\x16\n" +
+	"\x06ruleID\x18\x01 \x01(\tR\x06ruleID\"\x12\n" +
+	"\x10ListRulesRequest\"J\n" +
+	"\x11ListRulesResponse\x12\x1b\n" +
+	"\x05rules\x18\x01 \x03(\v2\x05.RuleR\x05rules\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"\x9b\x01\n" +
+	"\x12RuleChangeResponse\x12\x19\n" +
+	"\x04rule\x18\x01 \x01(\v2\x05.RuleR\x04rule\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12(\n" +
+	"\x0fmigratedBuckets\x18\x03 \x01(\x04R\x0fmigratedBuckets\x12&\n" +
+	"\x0edeletedBuckets\x18\x04 \x01(\x04R\x0edeletedBuckets2\xe4\x04\n" +
+	"\vRateLimiter\x12F\n" +
+	"\x0fGetAccessStatus\x12\x17.GetAccessStatusRequest\x1a\x18.GetAccessStatusResponse\"\x00\x12S\n" +
+	"\x12StreamAccessStatus\x12\x1a.StreamAccessStatusRequest\x1a\x1b.StreamAccessStatusResponse\"\x00(\x010\x01\x12U\n" +
+	"\x14BatchGetAccessStatus\x12\x1c.BatchGetAccessStatusRequest\x1a\x1d.BatchGetAccessStatusResponse\"\x00\x12J\n" +
+	"\x11CheckAccessStatus\x12\x17.GetAccessStatusRequest\x1a\x1a.CheckAccessStatusResponse\"\x00\x12.\n" +
+	"\aAcquire\x12\x0f.AcquireRequest\x1a\x10.AcquireResponse\"\x00\x12.\n" +
+	"\aRelease\x12\x0f.ReleaseRequest\x1a\x10.ReleaseResponse\"\x00\x12+\n" +
+	"\x06Refund\x12\x0e.RefundRequest\x1a\x0f.RefundResponse\"\x00\x12.\n" +
+	"\aReserve\x12\x0f.ReserveRequest\x1a\x10.ReserveResponse\"\x00\x12+\n" +
+	"\x06Commit\x12\x0e.CommitRequest\x1a\x0f.CommitResponse\"\x00\x12+\n" +
+	"\x06Cancel\x12\x0e.CancelRequest\x1a\x0f.CancelResponse\"\x002\xbb\x06\n" +
+	"\x10RateLimiterAdmin\x122\n" +
+	"\rCreateService\x12\x15.CreateServiceRequest\x1a\b.Service\"\x00\x122\n" +
+	"\rUpdateService\x12\x15.UpdateServiceRequest\x1a\b.Service\"\x00\x12,\n" +
+	"\n" +
+	"GetService\x12\x12.GetServiceRequest\x1a\b.Service\"\x00\x12=\n" +
+	"\fListServices\x12\x14.ListServicesRequest\x1a\x15.ListServicesResponse\"\x00\x12@\n" +
+	"\rDeleteService\x12\x15.DeleteServiceRequest\x1a\x16.DeleteServiceResponse\"\x00\x12)\n" +
+	"\tGetBucket\x12\x11.GetBucketRequest\x1a\a.Bucket\"\x00\x12:\n" +
+	"\vListBuckets\x12\x13.ListBucketsRequest\x1a\x14.ListBucketsResponse\"\x00\x12-\n" +
+	"\vResetBucket\x12\x13.ResetBucketRequest\x1a\a.Bucket\"\x00\x125\n" +
+	"\x0fSetBucketTokens\x12\x17.SetBucketTokensRequest\x1a\a.Bucket\"\x00\x12=\n" +
+	"\fDeleteBucket\x12\x14.DeleteBucketRequest\x1a\x15.DeleteBucketResponse\"\x00\x127\n" +
+	"\n" +
+	"CreateRule\x12\x12.CreateRuleRequest\x1a\x13.RuleChangeResponse\"\x00\x127\n" +
+	"\n" +
+	"UpdateRule\x12\x12.UpdateRuleRequest\x1a\x13.RuleChangeResponse\"\x00\x127\n" +
+	"\n" +
+	"DeleteRule\x12\x12.DeleteRuleRequest\x1a\x13.RuleChangeResponse\"\x00\x12#\n" +
+	"\aGetRule\x12\x0f.GetRuleRequest\x1a\x05.Rule\"\x00\x124\n" +
+	"\tListRules\x12\x11.ListRulesRequest\x1a\x12.ListRulesResponse\"\x00B\x15Z\x13rate-limiter-go/apib\x06proto3"
 
 var (
 	file_api_main_proto_rawDescOnce sync.Once
@@ -169,19 +3075,123 @@ func file_api_main_proto_rawDescGZIP() []byte {
 	return file_api_main_proto_rawDescData
 }
 
-var file_api_main_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_main_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_api_main_proto_goTypes = []any{
-	(*GetAccessStatusRequest)(nil),  // 0: GetAccessStatusRequest
-	(*GetAccessStatusResponse)(nil), // 1: GetAccessStatusResponse
+	(*GetAccessStatusRequest)(nil),       // 0: GetAccessStatusRequest
+	(*GetAccessStatusResponse)(nil),      // 1: GetAccessStatusResponse
+	(*CheckAccessStatusResponse)(nil),    // 2: CheckAccessStatusResponse
+	(*AcquireRequest)(nil),               // 3: AcquireRequest
+	(*AcquireResponse)(nil),              // 4: AcquireResponse
+	(*ReleaseRequest)(nil),               // 5: ReleaseRequest
+	(*ReleaseResponse)(nil),              // 6: ReleaseResponse
+	(*RefundRequest)(nil),                // 7: RefundRequest
+	(*RefundResponse)(nil),               // 8: RefundResponse
+	(*ReserveRequest)(nil),               // 9: ReserveRequest
+	(*ReserveResponse)(nil),              // 10: ReserveResponse
+	(*CommitRequest)(nil),                // 11: CommitRequest
+	(*CommitResponse)(nil),               // 12: CommitResponse
+	(*CancelRequest)(nil),                // 13: CancelRequest
+	(*CancelResponse)(nil),               // 14: CancelResponse
+	(*BatchGetAccessStatusRequest)(nil),  // 15: BatchGetAccessStatusRequest
+	(*BatchAccessStatusResult)(nil),      // 16: BatchAccessStatusResult
+	(*BatchGetAccessStatusResponse)(nil), // 17: BatchGetAccessStatusResponse
+	(*StreamAccessStatusRequest)(nil),    // 18: StreamAccessStatusRequest
+	(*StreamAccessStatusResponse)(nil),   // 19: StreamAccessStatusResponse
+	(*Service)(nil),                      // 20: Service
+	(*CreateServiceRequest)(nil),         // 21: CreateServiceRequest
+	(*UpdateServiceRequest)(nil),         // 22: UpdateServiceRequest
+	(*GetServiceRequest)(nil),            // 23: GetServiceRequest
+	(*ListServicesRequest)(nil),          // 24: ListServicesRequest
+	(*ListServicesResponse)(nil),         // 25: ListServicesResponse
+	(*DeleteServiceRequest)(nil),         // 26: DeleteServiceRequest
+	(*DeleteServiceResponse)(nil),        // 27: DeleteServiceResponse
+	(*Bucket)(nil),                       // 28: Bucket
+	(*GetBucketRequest)(nil),             // 29: GetBucketRequest
+	(*ListBucketsRequest)(nil),           // 30: ListBucketsRequest
+	(*ListBucketsResponse)(nil),          // 31: ListBucketsResponse
+	(*ResetBucketRequest)(nil),           // 32: ResetBucketRequest
+	(*SetBucketTokensRequest)(nil),       // 33: SetBucketTokensRequest
+	(*DeleteBucketRequest)(nil),          // 34: DeleteBucketRequest
+	(*DeleteBucketResponse)(nil),         // 35: DeleteBucketResponse
+	(*RuleLimit)(nil),                    // 36: RuleLimit
+	(*Rule)(nil),                         // 37: Rule
+	(*CreateRuleRequest)(nil),            // 38: CreateRuleRequest
+	(*UpdateRuleRequest)(nil),            // 39: UpdateRuleRequest
+	(*DeleteRuleRequest)(nil),            // 40: DeleteRuleRequest
+	(*GetRuleRequest)(nil),               // 41: GetRuleRequest
+	(*ListRulesRequest)(nil),             // 42: ListRulesRequest
+	(*ListRulesResponse)(nil),            // 43: ListRulesResponse
+	(*RuleChangeResponse)(nil),           // 44: RuleChangeResponse
 }
 var file_api_main_proto_depIdxs = []int32{
-	0, // 0: RateLimiter.GetAccessStatus:input_type -> GetAccessStatusRequest
-	1, // 1: RateLimiter.GetAccessStatus:output_type -> GetAccessStatusResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: BatchGetAccessStatusRequest.requests:type_name -> GetAccessStatusRequest
+	1,  // 1: BatchAccessStatusResult.status:type_name -> GetAccessStatusResponse
+	16, // 2: BatchGetAccessStatusResponse.results:type_name -> BatchAccessStatusResult
+	1,  // 3: BatchGetAccessStatusResponse.batchStatus:type_name -> GetAccessStatusResponse
+	0,  // 4: StreamAccessStatusRequest.request:type_name -> GetAccessStatusRequest
+	1,  // 5: StreamAccessStatusResponse.status:type_name -> GetAccessStatusResponse
+	20, // 6: ListServicesResponse.services:type_name -> Service
+	28, // 7: ListBucketsResponse.buckets:type_name -> Bucket
+	36, // 8: Rule.limits:type_name -> RuleLimit
+	37, // 9: CreateRuleRequest.rule:type_name -> Rule
+	37, // 10: UpdateRuleRequest.rule:type_name -> Rule
+	37, // 11: ListRulesResponse.rules:type_name -> Rule
+	37, // 12: RuleChangeResponse.rule:type_name -> Rule
+	0,  // 13: RateLimiter.GetAccessStatus:input_type -> GetAccessStatusRequest
+	18, // 14: RateLimiter.StreamAccessStatus:input_type -> StreamAccessStatusRequest
+	15, // 15: RateLimiter.BatchGetAccessStatus:input_type -> BatchGetAccessStatusRequest
+	0,  // 16: RateLimiter.CheckAccessStatus:input_type -> GetAccessStatusRequest
+	3,  // 17: RateLimiter.Acquire:input_type -> AcquireRequest
+	5,  // 18: RateLimiter.Release:input_type -> ReleaseRequest
+	7,  // 19: RateLimiter.Refund:input_type -> RefundRequest
+	9,  // 20: RateLimiter.Reserve:input_type -> ReserveRequest
+	11, // 21: RateLimiter.Commit:input_type -> CommitRequest
+	13, // 22: RateLimiter.Cancel:input_type -> CancelRequest
+	21, // 23: RateLimiterAdmin.CreateService:input_type -> CreateServiceRequest
+	22, // 24: RateLimiterAdmin.UpdateService:input_type -> UpdateServiceRequest
+	23, // 25: RateLimiterAdmin.GetService:input_type -> GetServiceRequest
+	24, // 26: RateLimiterAdmin.ListServices:input_type -> ListServicesRequest
+	26, // 27: RateLimiterAdmin.DeleteService:input_type -> DeleteServiceRequest
+	29, // 28: RateLimiterAdmin.GetBucket:input_type -> GetBucketRequest
+	30, // 29: RateLimiterAdmin.ListBuckets:input_type -> ListBucketsRequest
+	32, // 30: RateLimiterAdmin.ResetBucket:input_type -> ResetBucketRequest
+	33, // 31: RateLimiterAdmin.SetBucketTokens:input_type -> SetBucketTokensRequest
+	34, // 32: RateLimiterAdmin.DeleteBucket:input_type -> DeleteBucketRequest
+	38, // 33: RateLimiterAdmin.CreateRule:input_type -> CreateRuleRequest
+	39, // 34: RateLimiterAdmin.UpdateRule:input_type -> UpdateRuleRequest
+	40, // 35: RateLimiterAdmin.DeleteRule:input_type -> DeleteRuleRequest
+	41, // 36: RateLimiterAdmin.GetRule:input_type -> GetRuleRequest
+	42, // 37: RateLimiterAdmin.ListRules:input_type -> ListRulesRequest
+	1,  // 38: RateLimiter.GetAccessStatus:output_type -> GetAccessStatusResponse
+	19, // 39: RateLimiter.StreamAccessStatus:output_type -> StreamAccessStatusResponse
+	17, // 40: RateLimiter.BatchGetAccessStatus:output_type -> BatchGetAccessStatusResponse
+	2,  // 41: RateLimiter.CheckAccessStatus:output_type -> CheckAccessStatusResponse
+	4,  // 42: RateLimiter.Acquire:output_type -> AcquireResponse
+	6,  // 43: RateLimiter.Release:output_type -> ReleaseResponse
+	8,  // 44: RateLimiter.Refund:output_type -> RefundResponse
+	10, // 45: RateLimiter.Reserve:output_type -> ReserveResponse
+	12, // 46: RateLimiter.Commit:output_type -> CommitResponse
+	14, // 47: RateLimiter.Cancel:output_type -> CancelResponse
+	20, // 48: RateLimiterAdmin.CreateService:output_type -> Service
+	20, // 49: RateLimiterAdmin.UpdateService:output_type -> Service
+	20, // 50: RateLimiterAdmin.GetService:output_type -> Service
+	25, // 51: RateLimiterAdmin.ListServices:output_type -> ListServicesResponse
+	27, // 52: RateLimiterAdmin.DeleteService:output_type -> DeleteServiceResponse
+	28, // 53: RateLimiterAdmin.GetBucket:output_type -> Bucket
+	31, // 54: RateLimiterAdmin.ListBuckets:output_type -> ListBucketsResponse
+	28, // 55: RateLimiterAdmin.ResetBucket:output_type -> Bucket
+	28, // 56: RateLimiterAdmin.SetBucketTokens:output_type -> Bucket
+	35, // 57: RateLimiterAdmin.DeleteBucket:output_type -> DeleteBucketResponse
+	44, // 58: RateLimiterAdmin.CreateRule:output_type -> RuleChangeResponse
+	44, // 59: RateLimiterAdmin.UpdateRule:output_type -> RuleChangeResponse
+	44, // 60: RateLimiterAdmin.DeleteRule:output_type -> RuleChangeResponse
+	37, // 61: RateLimiterAdmin.GetRule:output_type -> Rule
+	43, // 62: RateLimiterAdmin.ListRules:output_type -> ListRulesResponse
+	38, // [38:63] is the sub-list for method output_type
+	13, // [13:38] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_api_main_proto_init() }
@@ -195,9 +3205,9 @@ func file_api_main_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_main_proto_rawDesc), len(file_api_main_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   45,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_api_main_proto_goTypes,
 		DependencyIndexes: file_api_main_proto_depIdxs,
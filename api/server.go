@@ -2,14 +2,19 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
 	"rate-limiter-go/limiter"
 )
 
+var ErrWaitInAllOrNothingBatch = errors.New("waitForCapacity is not supported for the requests of an allOrNothing batch")
+
 type Server struct {
 	UnimplementedRateLimiterServer
-	BucketStorage   limiter.BucketStorage
-	ServiceRegistry limiter.ServiceRegistry
+	BucketStorage      limiter.BucketStorage
+	ServiceRegistry    limiter.ServiceRegistry
+	RuleRegistry       limiter.RuleRegistry
+	ConcurrencyStorage limiter.ConcurrencyStorage
 }
 
 func (s *Server) GetAccessStatus(ctx context.Context, req *GetAccessStatusRequest) (*GetAccessStatusResponse, error) {
@@ -20,41 +25,286 @@ func (s *Server) GetAccessStatus(ctx context.Context, req *GetAccessStatusReques
 		return nil, err
 	}
 
-	bucketID := limiter.GetBucketID(limiter.GetBucketIDRequest{
-		ServiceID: req.ServiceID,
-		ClientID:  req.ClientID,
-		UserID:    req.UserID,
-	})
-	bucket, err := s.BucketStorage.GetBucket(bucketID)
-
-	if bucket == nil {
-		s.BucketStorage.CreateBucket(limiter.CreateBucketReqBody{
-			ID:                  bucketID,
-			InitialTokens:       100,
-			RefillRatePerSecond: 1,
-			MaxTokens:           100,
-		})
-	}
-
-	accessRes, err := s.BucketStorage.ConsumeService(limiter.ConsumeServiceRequest{
+	consumeReq := limiter.ConsumeServiceRequest{
 		ServiceID:   req.ServiceID,
 		ClientID:    req.ClientID,
 		UserID:      req.UserID,
+		UserTier:    req.UserTier,
 		UsageAmount: req.UsageAmountReq,
-	})
+	}
+	var accessRes limiter.AccessStatusResponse
+	if req.WaitForCapacity {
+		accessRes, err = s.BucketStorage.WaitService(ctx, consumeReq)
+	} else {
+		accessRes, err = s.BucketStorage.ConsumeService(consumeReq)
+	}
 	if err != nil {
 		log.Printf("level=error event=consume_service status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
 		return nil, err
 	}
 	log.Printf(
-		"level=info event=get_access_status status=success client_id=%q service_id=%q: allowed=%t retry_after=%d",
+		"level=info event=get_access_status status=success client_id=%q service_id=%q: allowed=%t retry_after=%d delay_ms=%d rule_id=%q denied_level=%q denied_limit=%q",
 		req.ClientID,
 		req.ServiceID,
 		accessRes.IsAllowed,
 		accessRes.RetryAfterSeconds,
+		accessRes.DelayMilliseconds,
+		accessRes.RuleID,
+		accessRes.DeniedLevel,
+		accessRes.DeniedLimit,
 	)
+	return toGetAccessStatusResponse(accessRes), nil
+}
+
+// BatchGetAccessStatus answers several GetAccessStatus requests in one call.
+// Without allOrNothing every request is consumed on its own, waiting for
+// capacity when it asks to, and failures are reported per request. With
+// allOrNothing nothing is consumed unless every request is allowed: every
+// result tells whether its own request fits, and BatchStatus carries the
+// answer for the batch. Such batches never wait, so requests that set
+// waitForCapacity are rejected.
+func (s *Server) BatchGetAccessStatus(ctx context.Context, req *BatchGetAccessStatusRequest) (*BatchGetAccessStatusResponse, error) {
+	log.Printf("level=info event=batch_get_access_status requests=%d all_or_nothing=%t", len(req.Requests), req.AllOrNothing)
+	res := &BatchGetAccessStatusResponse{
+		Results:   make([]*BatchAccessStatusResult, 0, len(req.Requests)),
+		IsAllowed: true,
+	}
+	if !req.AllOrNothing {
+		for _, item := range req.Requests {
+			status, err := s.GetAccessStatus(ctx, item)
+			if err != nil {
+				res.Results = append(res.Results, &BatchAccessStatusResult{Error: err.Error()})
+				res.IsAllowed = false
+				continue
+			}
+			res.Results = append(res.Results, &BatchAccessStatusResult{Status: status})
+			res.IsAllowed = res.IsAllowed && status.IsAllowed
+		}
+		return res, nil
+	}
+
+	bodies := make([]limiter.ConsumeServiceRequest, 0, len(req.Requests))
+	for _, item := range req.Requests {
+		if item.WaitForCapacity {
+			log.Printf("level=error event=batch_get_access_status status=error service_id=%q client_id=%q: error=%q", item.ServiceID, item.ClientID, ErrWaitInAllOrNothingBatch)
+			return nil, ErrWaitInAllOrNothingBatch
+		}
+		bodies = append(bodies, limiter.ConsumeServiceRequest{
+			ServiceID:   item.ServiceID,
+			ClientID:    item.ClientID,
+			UserID:      item.UserID,
+			UserTier:    item.UserTier,
+			UsageAmount: item.UsageAmountReq,
+		})
+	}
+	accessRes, itemRes, err := s.BucketStorage.ConsumeServices(bodies)
+	if err != nil {
+		log.Printf("level=error event=consume_services status=error requests=%d: error=%q", len(bodies), err)
+		return nil, err
+	}
+	log.Printf("level=info event=batch_get_access_status status=success requests=%d: allowed=%t retry_after=%d denied_level=%q denied_limit=%q", len(bodies), accessRes.IsAllowed, accessRes.RetryAfterSeconds, accessRes.DeniedLevel, accessRes.DeniedLimit)
+	// Every item gets its own verdict, so a denied batch tells which items
+	// did not fit
+	for _, item := range itemRes {
+		res.Results = append(res.Results, &BatchAccessStatusResult{Status: toGetAccessStatusResponse(item)})
+	}
+	res.IsAllowed = accessRes.IsAllowed
+	res.BatchStatus = toGetAccessStatusResponse(accessRes)
+	return res, nil
+}
+
+func toGetAccessStatusResponse(accessRes limiter.AccessStatusResponse) *GetAccessStatusResponse {
 	return &GetAccessStatusResponse{
-		IsAllowed:         accessRes.IsAllowed,
-		RetryAfterSeconds: accessRes.RetryAfterSeconds,
+		IsAllowed:              accessRes.IsAllowed,
+		RetryAfterSeconds:      accessRes.RetryAfterSeconds,
+		DelayMilliseconds:      accessRes.DelayMilliseconds,
+		DeniedLimit:            accessRes.DeniedLimit,
+		DeniedLevel:            string(accessRes.DeniedLevel),
+		Remaining:              accessRes.Remaining,
+		Limit:                  accessRes.Limit,
+		ResetAfterSeconds:      accessRes.ResetAfterSeconds,
+		RuleID:                 accessRes.RuleID,
+		RetryAfterMilliseconds: accessRes.RetryAfterMilliseconds,
+	}
+}
+
+// CheckAccessStatus reports whether GetAccessStatus would allow the request
+// and how much capacity is left, without consuming anything.
+func (s *Server) CheckAccessStatus(ctx context.Context, req *GetAccessStatusRequest) (*CheckAccessStatusResponse, error) {
+	log.Printf("level=info event=check_access_status service_id=%s client_id=%s user_id=%s usage_amount=%d", req.ServiceID, req.ClientID, req.UserID, req.UsageAmountReq)
+	_, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err != nil {
+		log.Printf("level=error event=get_service_by_id status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+
+	accessRes, err := s.BucketStorage.CheckService(limiter.ConsumeServiceRequest{
+		ServiceID:   req.ServiceID,
+		ClientID:    req.ClientID,
+		UserID:      req.UserID,
+		UserTier:    req.UserTier,
+		UsageAmount: req.UsageAmountReq,
+	})
+	if err != nil {
+		log.Printf("level=error event=check_service status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
+		return nil, err
+	}
+	return &CheckAccessStatusResponse{
+		IsAllowed:              accessRes.IsAllowed,
+		RetryAfterSeconds:      accessRes.RetryAfterSeconds,
+		Remaining:              accessRes.Remaining,
+		Limit:                  accessRes.Limit,
+		ResetAfterSeconds:      accessRes.ResetAfterSeconds,
+		DeniedLimit:            accessRes.DeniedLimit,
+		DeniedLevel:            string(accessRes.DeniedLevel),
+		RuleID:                 accessRes.RuleID,
+		RetryAfterMilliseconds: accessRes.RetryAfterMilliseconds,
+	}, nil
+}
+
+func (s *Server) Acquire(ctx context.Context, req *AcquireRequest) (*AcquireResponse, error) {
+	log.Printf("level=info event=acquire service_id=%s client_id=%s user_id=%s", req.ServiceID, req.ClientID, req.UserID)
+	_, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err != nil {
+		log.Printf("level=error event=get_service_by_id status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+
+	acquireRes, err := s.ConcurrencyStorage.Acquire(limiter.AcquireRequest{
+		ServiceID: req.ServiceID,
+		ClientID:  req.ClientID,
+		UserID:    req.UserID,
+		UserTier:  req.UserTier,
+	})
+	if err != nil {
+		log.Printf("level=error event=acquire status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
+		return nil, err
+	}
+	log.Printf("level=info event=acquire status=success client_id=%q service_id=%q: allowed=%t lease_id=%q retry_after=%d", req.ClientID, req.ServiceID, acquireRes.IsAllowed, acquireRes.LeaseID, acquireRes.RetryAfterSeconds)
+	res := &AcquireResponse{
+		IsAllowed:         acquireRes.IsAllowed,
+		LeaseID:           acquireRes.LeaseID,
+		RetryAfterSeconds: acquireRes.RetryAfterSeconds,
+	}
+	if acquireRes.IsAllowed {
+		res.LeaseExpiresAtUnix = acquireRes.ExpiresAt.Unix()
+	}
+	return res, nil
+}
+
+func (s *Server) Release(ctx context.Context, req *ReleaseRequest) (*ReleaseResponse, error) {
+	log.Printf("level=info event=release service_id=%s client_id=%s user_id=%s lease_id=%s", req.ServiceID, req.ClientID, req.UserID, req.LeaseID)
+	err := s.ConcurrencyStorage.Release(limiter.ReleaseRequest{
+		ServiceID: req.ServiceID,
+		ClientID:  req.ClientID,
+		UserID:    req.UserID,
+		LeaseID:   req.LeaseID,
+	})
+	if err == limiter.ErrLeaseNotFound || err == limiter.ErrSemaphoreNotFound {
+		// The lease already expired or was never handed out, there is
+		// nothing left to release.
+		return &ReleaseResponse{Released: false}, nil
+	}
+	if err != nil {
+		log.Printf("level=error event=release status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
+		return nil, err
+	}
+	return &ReleaseResponse{Released: true}, nil
+}
+
+// Refund gives back tokens consumed by an earlier GetAccessStatus call, for
+// example when the admitted work failed. Retrying with the same idempotency
+// key does not refund twice.
+func (s *Server) Refund(ctx context.Context, req *RefundRequest) (*RefundResponse, error) {
+	log.Printf("level=info event=refund service_id=%s client_id=%s user_id=%s usage_amount=%d idempotency_key=%q", req.ServiceID, req.ClientID, req.UserID, req.UsageAmountReq, req.IdempotencyKey)
+	_, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err != nil {
+		log.Printf("level=error event=get_service_by_id status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+
+	refundRes, err := s.BucketStorage.RefundService(limiter.RefundServiceRequest{
+		ServiceID:      req.ServiceID,
+		ClientID:       req.ClientID,
+		UserID:         req.UserID,
+		UserTier:       req.UserTier,
+		UsageAmount:    req.UsageAmountReq,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		log.Printf("level=error event=refund_service status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
+		return nil, err
+	}
+	log.Printf("level=info event=refund status=success client_id=%q service_id=%q: refunded=%t remaining=%d", req.ClientID, req.ServiceID, refundRes.Refunded, refundRes.Remaining)
+	return &RefundResponse{
+		Refunded:  refundRes.Refunded,
+		Remaining: refundRes.Remaining,
+		Limit:     refundRes.Limit,
+	}, nil
+}
+
+// Reserve takes the estimated usage of a long running job up front. The job
+// later calls Commit with its actual usage, or Cancel if it did not run.
+func (s *Server) Reserve(ctx context.Context, req *ReserveRequest) (*ReserveResponse, error) {
+	log.Printf("level=info event=reserve service_id=%s client_id=%s user_id=%s usage_amount=%d ttl_seconds=%d", req.ServiceID, req.ClientID, req.UserID, req.UsageAmountReq, req.TtlSeconds)
+	_, err := s.ServiceRegistry.GetService(req.ServiceID)
+	if err != nil {
+		log.Printf("level=error event=get_service_by_id status=error service_id=%q: error=%q", req.ServiceID, err)
+		return nil, err
+	}
+
+	reserveRes, err := s.BucketStorage.Reserve(limiter.ReserveRequest{
+		ServiceID:   req.ServiceID,
+		ClientID:    req.ClientID,
+		UserID:      req.UserID,
+		UserTier:    req.UserTier,
+		UsageAmount: req.UsageAmountReq,
+		TTLSeconds:  req.TtlSeconds,
+	})
+	if err != nil {
+		log.Printf("level=error event=reserve status=error client_id=%q service_id=%q: error=%q", req.ClientID, req.ServiceID, err)
+		return nil, err
+	}
+	res := &ReserveResponse{
+		IsAllowed:         reserveRes.IsAllowed,
+		RetryAfterSeconds: reserveRes.RetryAfterSeconds,
+		ReservationID:     reserveRes.ReservationID,
+		DeniedLimit:       reserveRes.DeniedLimit,
+		DeniedLevel:       string(reserveRes.DeniedLevel),
+	}
+	if reserveRes.IsAllowed {
+		res.ExpiresAtUnix = reserveRes.ExpiresAt.Unix()
+	}
+	return res, nil
+}
+
+func (s *Server) Commit(ctx context.Context, req *CommitRequest) (*CommitResponse, error) {
+	log.Printf("level=info event=commit reservation_id=%s usage_amount=%d", req.ReservationID, req.UsageAmountReq)
+	commitRes, err := s.BucketStorage.Commit(limiter.CommitRequest{
+		ReservationID: req.ReservationID,
+		UsageAmount:   req.UsageAmountReq,
+	})
+	if err != nil {
+		log.Printf("level=error event=commit status=error reservation_id=%q: error=%q", req.ReservationID, err)
+		return nil, err
+	}
+	return &CommitResponse{
+		Remaining: commitRes.Remaining,
+		Limit:     commitRes.Limit,
 	}, nil
 }
+
+func (s *Server) Cancel(ctx context.Context, req *CancelRequest) (*CancelResponse, error) {
+	log.Printf("level=info event=cancel reservation_id=%s", req.ReservationID)
+	err := s.BucketStorage.Cancel(req.ReservationID)
+	if err == limiter.ErrReservationNotFound {
+		// The reservation was already committed, cancelled or released
+		// when it expired.
+		return &CancelResponse{Cancelled: false}, nil
+	}
+	if err != nil {
+		log.Printf("level=error event=cancel status=error reservation_id=%q: error=%q", req.ReservationID, err)
+		return nil, err
+	}
+	return &CancelResponse{Cancelled: true}, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log"
+	"sync"
+)
+
+// StreamWorkers is how many requests of one StreamAccessStatus stream are
+// answered concurrently.
+const StreamWorkers = 8
+
+// StreamQueueSize is how many received requests of one stream may wait for
+// a worker. Once the queue is full the server stops reading from the
+// stream, and gRPC flow control slows the client down.
+const StreamQueueSize = 256
+
+var ErrMissingStreamRequest = errors.New("stream message has no request")
+
+// StreamAccessStatus answers GetAccessStatus requests sent over a single
+// stream. Responses are sent as soon as they are ready, which is not
+// necessarily the order the requests came in, and carry the requestID of
+// the request they answer.
+func (s *Server) StreamAccessStatus(stream RateLimiter_StreamAccessStatusServer) error {
+	ctx := stream.Context()
+	log.Printf("level=info event=stream_access_status status=started")
+	requests := make(chan *StreamAccessStatusRequest, StreamQueueSize)
+	responses := make(chan *StreamAccessStatusResponse, StreamQueueSize)
+
+	var workers sync.WaitGroup
+	for range StreamWorkers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for req := range requests {
+				res := &StreamAccessStatusResponse{RequestID: req.RequestID}
+				if req.Request == nil {
+					res.Error = ErrMissingStreamRequest.Error()
+				} else {
+					status, err := s.GetAccessStatus(ctx, req.Request)
+					if err != nil {
+						res.Error = err.Error()
+					}
+					res.Status = status
+				}
+				select {
+				case responses <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(responses)
+	}()
+
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(requests)
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				recvErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	for res := range responses {
+		err := stream.Send(res)
+		if err != nil {
+			log.Printf("level=error event=stream_access_status status=error request_id=%q: error=%q", res.RequestID, err)
+			return err
+		}
+	}
+	err := <-recvErr
+	if err != nil {
+		log.Printf("level=error event=stream_access_status status=error: error=%q", err)
+		return err
+	}
+	log.Printf("level=info event=stream_access_status status=finished")
+	return nil
+}
@@ -3,20 +3,76 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"math"
+	"rate-limiter-go/limiter"
+	"reflect"
+	"slices"
+	"strings"
 )
 
-var ErrInvalidMaxTokens = errors.New("max token should be specified for every rule with a value > 0")
+// ErrInvalidMaxTokens is kept for callers that check for it; rules and limits
+// are validated by the limiter package, whose errors the problems carry.
+var ErrInvalidMaxTokens = limiter.ErrInvalidMaxTokens
+var ErrDuplicateRuleID = errors.New("rule id is used by another rule")
+var ErrUnknownService = errors.New("service is not registered by any rule")
+var ErrNegativeValue = errors.New("value should not be negative")
+var ErrWrongType = errors.New("value has the wrong type")
+var ErrEmptyPoolID = errors.New("pool id should not be empty")
+var ErrDuplicatePoolID = errors.New("pool id is used by another pool")
+var ErrDuplicateServiceID = errors.New("service is listed more than once")
 
 type LimitRule struct {
 	ID                  string `json:"id"`
+	Level               string `json:"level"`
 	ClientID            string `json:"client_id"`
+	OrgID               string `json:"org_id"`
 	ServiceID           string `json:"service_id"`
+	UserID              string `json:"user_id"`
+	UserTier            string `json:"user_tier"`
 	UsagePrice          uint64 `json:"usage_price"`
+	Algorithm           string `json:"algorithm"`
 	RefillRatePerSecond uint64 `json:"refill_rate_per_second"`
 	InitialTokens       uint64 `json:"initial_tokens"`
 	MaxTokens           uint64 `json:"max_tokens"`
+	WindowSeconds       uint64 `json:"window_seconds"`
+	Period              string `json:"period"`
+	TimeZone            string `json:"time_zone"`
+	MaxConcurrent       uint64 `json:"max_concurrent"`
+	LeaseTTLSeconds     uint64 `json:"lease_ttl_seconds"`
+	// Limits replaces the single limit described by the fields above with
+	// several limits that are all enforced at once.
+	Limits []Limit `json:"limits"`
+}
+
+type Limit struct {
+	Name                string `json:"name"`
+	Algorithm           string `json:"algorithm"`
+	RefillRatePerSecond uint64 `json:"refill_rate_per_second"`
+	InitialTokens       uint64 `json:"initial_tokens"`
+	MaxTokens           uint64 `json:"max_tokens"`
+	WindowSeconds       uint64 `json:"window_seconds"`
+	Period              string `json:"period"`
+	TimeZone            string `json:"time_zone"`
+}
+
+// Organization groups clients whose usage is limited together by
+// organization level rules.
+type Organization struct {
+	ID        string   `json:"id"`
+	ClientIDs []string `json:"client_ids"`
+}
+
+// Pool lets several services draw from one shared bucket per client. The
+// pool's limit is described by the embedded Limit fields, or by Limits when
+// it has several.
+type Pool struct {
+	ID         string   `json:"id"`
+	ServiceIDs []string `json:"service_ids"`
+	Limit
+	Limits []Limit `json:"limits"`
 }
 
 type PersistenceSettings struct {
@@ -25,35 +81,430 @@ type PersistenceSettings struct {
 }
 
 type Config struct {
-	Rules               []LimitRule         `json:"rules"`
+	Rules []LimitRule `json:"rules"`
+	// DefaultRule applies to requests that match none of Rules. When it is
+	// omitted limiter.DefaultRule is used.
+	DefaultRule         *LimitRule          `json:"default_rule"`
+	Organizations       []Organization      `json:"organizations"`
+	Pools               []Pool              `json:"pools"`
 	PersistenceSettings PersistenceSettings `json:"persistence_settings"`
 }
 
 type ConfigParser interface {
-	Parse(io.Reader) *Config
+	// Parse reads a config. A config that is not valid JSON returns the
+	// decoding error, and a config with invalid values a *ValidationError
+	// listing all of them.
+	Parse(io.Reader) (*Config, error)
+}
+
+// Problem is one invalid value of a config.
+type Problem struct {
+	// Section is "rules", "default_rule", "organizations", "pools" or
+	// "persistence_settings".
+	Section string
+	// Index is the position of the rule or pool in its section, or -1 when
+	// the problem is with the section itself.
+	Index int
+	// ID is the id of the rule or pool, if it has one.
+	ID    string
+	Field string
+	Err   error
+}
+
+func (p Problem) String() string {
+	location := p.Section
+	if (p.Section == "rules" || p.Section == "organizations" || p.Section == "pools") && p.Index >= 0 {
+		location = fmt.Sprintf("%s[%d]", p.Section, p.Index)
+	}
+	return fmt.Sprintf("%s id=%q field=%s: %v", location, p.ID, p.Field, p.Err)
+}
+
+// ValidationError lists every problem found in a config.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid config: %d problem(s)", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap lets errors.Is find the error of any problem.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		errs = append(errs, p.Err)
+	}
+	return errs
 }
 
 type jsonParser struct{}
 
-func (j *jsonParser) Parse(in io.Reader) *Config {
-	var config Config
-	err := json.NewDecoder(in).Decode(&config)
+func (j *jsonParser) Parse(in io.Reader) (*Config, error) {
+	data, err := io.ReadAll(in)
 	if err != nil {
+		log.Printf("event=failed_to_read_config err=%q", err)
+		return nil, err
+	}
+	var config Config
+	err = json.Unmarshal(data, &config)
+	// A value of the wrong type leaves its field empty and the rest of the
+	// config is still decoded, so the other problems can be listed as well
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
 		log.Printf("event=failed_to_parse_json_config err=%q", err)
-		return nil
+		return nil, err
+	}
+	v := &validator{}
+	v.findNegativeValues(data)
+	if typeErr != nil {
+		v.findTypeErrors(&config, data)
+		if len(v.problems) == 0 {
+			section, _, _ := strings.Cut(typeErr.Field, ".")
+			v.add(Problem{Section: section, Field: typeErr.Field, Err: fmt.Errorf("%w: %v", ErrWrongType, typeErr)})
+		}
+	}
+	v.validateConfig(&config)
+	if len(v.problems) > 0 {
+		err := &ValidationError{Problems: v.problems}
+		log.Printf("event=invalid_config problems=%d err=%q", len(v.problems), err)
+		return nil, err
+	}
+	return &config, nil
+}
+
+var sections = []string{"persistence_settings", "default_rule", "rules", "organizations", "pools"}
+
+type validator struct {
+	problems []Problem
+}
+
+// add records a problem, unless the same field already has one. A negative
+// value is decoded as 0, which must not be reported a second time.
+func (v *validator) add(p Problem) {
+	exists := slices.ContainsFunc(v.problems, func(q Problem) bool {
+		return q.Section == p.Section && q.Index == p.Index && q.Field == p.Field
+	})
+	if !exists {
+		v.problems = append(v.problems, p)
 	}
-	validateConfig(&config)
-	return &config
 }
 
-func validateConfig(c *Config) {
-	for _, rule := range c.Rules {
-		log.Printf("max tokens = %d", rule.MaxTokens)
-		if rule.MaxTokens <= 0 {
+// findNegativeValues reports the negative numbers of the config, which the
+// unsigned fields of Config cannot hold.
+func (v *validator) findNegativeValues(data []byte) {
+	var raw struct {
+		Rules               []map[string]any `json:"rules"`
+		DefaultRule         map[string]any   `json:"default_rule"`
+		Pools               []map[string]any `json:"pools"`
+		PersistenceSettings map[string]any   `json:"persistence_settings"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return
+	}
+	v.findNegativeFields("persistence_settings", 0, raw.PersistenceSettings)
+	v.findNegativeFields("default_rule", 0, raw.DefaultRule)
+	for i, rule := range raw.Rules {
+		v.findNegativeFields("rules", i, rule)
+	}
+	for i, pool := range raw.Pools {
+		v.findNegativeFields("pools", i, pool)
+	}
+}
 
-			panic(ErrInvalidMaxTokens)
+func (v *validator) findNegativeFields(section string, index int, fields map[string]any) {
+	id, _ := fields["id"].(string)
+	var find func(prefix string, fields map[string]any)
+	find = func(prefix string, fields map[string]any) {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			switch value := fields[name].(type) {
+			case float64:
+				if value < 0 {
+					v.add(Problem{Section: section, Index: index, ID: id, Field: prefix + name, Err: ErrNegativeValue})
+				}
+			case []any:
+				if name != "limits" {
+					continue
+				}
+				for j, limit := range value {
+					limitFields, ok := limit.(map[string]any)
+					if ok {
+						find(fmt.Sprintf("%slimits[%d].", prefix, j), limitFields)
+					}
+				}
+			}
 		}
 	}
+	find("", fields)
+}
+
+// findTypeErrors reports the values that do not fit the type of their
+// field. The decoder only returns the first of them. Negative numbers are
+// reported by findNegativeValues instead.
+func (v *validator) findTypeErrors(c *Config, data []byte) {
+	var raw map[string]any
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return
+	}
+	configType := reflect.TypeOf(*c)
+	for i := range configType.NumField() {
+		field := configType.Field(i)
+		section, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		value, ok := raw[section]
+		if !ok {
+			continue
+		}
+		elems, isList := value.([]any)
+		if field.Type.Kind() != reflect.Slice || !isList {
+			index := 0
+			if field.Type.Kind() == reflect.Slice {
+				index = -1
+			}
+			findValueTypeErrors("", value, field.Type, v.adder(c, section, index))
+			continue
+		}
+		for index, elem := range elems {
+			findValueTypeErrors("", elem, field.Type.Elem(), v.adder(c, section, index))
+		}
+	}
+}
+
+// adder returns a function that reports problems of the rule, organization
+// or pool at the index of the section.
+func (v *validator) adder(c *Config, section string, index int) func(field string, err error) {
+	id := ""
+	switch {
+	case section == "rules" && index >= 0 && index < len(c.Rules):
+		id = c.Rules[index].ID
+	case section == "pools" && index >= 0 && index < len(c.Pools):
+		id = c.Pools[index].ID
+	case section == "organizations" && index >= 0 && index < len(c.Organizations):
+		id = c.Organizations[index].ID
+	case section == "default_rule" && c.DefaultRule != nil:
+		id = c.DefaultRule.ID
+	}
+	return func(field string, err error) {
+		v.add(Problem{Section: section, Index: index, ID: id, Field: field, Err: err})
+	}
+}
+
+func findValueTypeErrors(field string, value any, t reflect.Type, add func(field string, err error)) {
+	if value == nil {
+		// null leaves the field empty
+		return
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	wrongType := func() {
+		add(field, fmt.Errorf("%w: %s into %s", ErrWrongType, jsonKind(value), t))
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			wrongType()
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			wrongType()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := value.(float64)
+		if !ok || (n >= 0 && (n != math.Trunc(n) || n >= math.Ldexp(1, t.Bits()))) {
+			wrongType()
+		}
+	case reflect.Slice:
+		elems, ok := value.([]any)
+		if !ok {
+			wrongType()
+			return
+		}
+		for j, elem := range elems {
+			findValueTypeErrors(fmt.Sprintf("%s[%d]", field, j), elem, t.Elem(), add)
+		}
+	case reflect.Struct:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			wrongType()
+			return
+		}
+		findFieldTypeErrors(field, fields, t, add)
+	}
+}
+
+func findFieldTypeErrors(prefix string, fields map[string]any, t reflect.Type, add func(field string, err error)) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			// The fields of an embedded struct are decoded as if they were
+			// declared by t
+			findFieldTypeErrors(prefix, fields, field.Type, add)
+			continue
+		}
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		findValueTypeErrors(name, value, field.Type, add)
+	}
+}
+
+// jsonKind describes a decoded JSON value like the decoder's errors do.
+func jsonKind(value any) string {
+	switch value := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		return fmt.Sprintf("number %v", value)
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func (v *validator) validateConfig(c *Config) {
+	if c.DefaultRule != nil {
+		v.validateRule("default_rule", 0, *c.DefaultRule)
+	}
+
+	services := make(map[string]bool)
+	ruleIndexes := make(map[string]int)
+	for i, rule := range c.Rules {
+		if first, ok := ruleIndexes[rule.ID]; ok {
+			v.add(Problem{Section: "rules", Index: i, ID: rule.ID, Field: "id", Err: fmt.Errorf("%w: rules[%d]", ErrDuplicateRuleID, first)})
+		} else {
+			ruleIndexes[rule.ID] = i
+		}
+		if !limiter.IsPattern(rule.ServiceID) {
+			services[rule.ServiceID] = true
+		}
+		v.validateRule("rules", i, rule)
+	}
+
+	poolIndexes := make(map[string]int)
+	for i, pool := range c.Pools {
+		if pool.ID == "" {
+			v.add(Problem{Section: "pools", Index: i, ID: pool.ID, Field: "id", Err: ErrEmptyPoolID})
+		} else if first, ok := poolIndexes[pool.ID]; ok {
+			v.add(Problem{Section: "pools", Index: i, ID: pool.ID, Field: "id", Err: fmt.Errorf("%w: pools[%d]", ErrDuplicatePoolID, first)})
+		} else {
+			poolIndexes[pool.ID] = i
+		}
+		for j, serviceID := range pool.ServiceIDs {
+			field := fmt.Sprintf("service_ids[%d]", j)
+			if first := slices.Index(pool.ServiceIDs, serviceID); first < j {
+				v.add(Problem{Section: "pools", Index: i, ID: pool.ID, Field: field, Err: fmt.Errorf("%w: service_ids[%d]", ErrDuplicateServiceID, first)})
+			} else if !services[serviceID] {
+				v.add(Problem{Section: "pools", Index: i, ID: pool.ID, Field: field, Err: fmt.Errorf("%w: %q", ErrUnknownService, serviceID)})
+			}
+		}
+		var errs []limiter.FieldError
+		if len(pool.Limits) == 0 {
+			errs = limiter.LimitFieldErrors("", toLimiterLimit(pool.Limit))
+		} else {
+			errs = limiter.LimitsFieldErrors(toLimiterLimits(pool.Limits))
+		}
+		v.addFieldErrors("pools", i, pool.ID, errs)
+	}
+
+	slices.SortStableFunc(v.problems, func(a, b Problem) int {
+		if a.Section != b.Section {
+			return slices.Index(sections, a.Section) - slices.Index(sections, b.Section)
+		}
+		return a.Index - b.Index
+	})
+}
+
+// validateRule checks a rule the same way the limiter checks the rules of
+// the admin API, so that a config that starts also reloads.
+func (v *validator) validateRule(section string, index int, rule LimitRule) {
+	v.addFieldErrors(section, index, rule.ID, limiter.RuleFieldErrors(rule.ToLimiterRule()))
+}
+
+func (v *validator) addFieldErrors(section string, index int, id string, errs []limiter.FieldError) {
+	for _, err := range errs {
+		v.add(Problem{Section: section, Index: index, ID: id, Field: err.Field, Err: err.Err})
+	}
+}
+
+// ToLimiterRule returns the rule the limiter enforces for a rule of the
+// config.
+func (rule LimitRule) ToLimiterRule() limiter.Rule {
+	return limiter.Rule{
+		ID:                  rule.ID,
+		Level:               limiter.Level(rule.Level),
+		ServiceID:           rule.ServiceID,
+		ClientID:            rule.ClientID,
+		OrgID:               rule.OrgID,
+		UserID:              rule.UserID,
+		UserTier:            rule.UserTier,
+		Algorithm:           limiter.Algorithm(rule.Algorithm),
+		RefillRatePerSecond: rule.RefillRatePerSecond,
+		InitialTokens:       rule.InitialTokens,
+		MaxTokens:           rule.MaxTokens,
+		WindowSeconds:       rule.WindowSeconds,
+		Period:              limiter.Period(rule.Period),
+		TimeZone:            rule.TimeZone,
+		MaxConcurrent:       rule.MaxConcurrent,
+		LeaseTTLSeconds:     rule.LeaseTTLSeconds,
+		Limits:              toLimiterLimits(rule.Limits),
+	}
+}
+
+// ToLimiterPool returns the pool the limiter enforces for a pool of the
+// config.
+func (pool Pool) ToLimiterPool() limiter.Pool {
+	limits := pool.Limits
+	if len(limits) == 0 {
+		limits = []Limit{pool.Limit}
+	}
+	return limiter.Pool{
+		ID:         pool.ID,
+		ServiceIDs: pool.ServiceIDs,
+		Limits:     toLimiterLimits(limits),
+	}
+}
+
+func toLimiterLimits(limits []Limit) []limiter.Limit {
+	if len(limits) == 0 {
+		return nil
+	}
+	res := make([]limiter.Limit, 0, len(limits))
+	for _, limit := range limits {
+		res = append(res, toLimiterLimit(limit))
+	}
+	return res
+}
+
+func toLimiterLimit(limit Limit) limiter.Limit {
+	return limiter.Limit{
+		Name:                limit.Name,
+		Algorithm:           limiter.Algorithm(limit.Algorithm),
+		RefillRatePerSecond: limit.RefillRatePerSecond,
+		InitialTokens:       limit.InitialTokens,
+		MaxTokens:           limit.MaxTokens,
+		WindowSeconds:       limit.WindowSeconds,
+		Period:              limiter.Period(limit.Period),
+		TimeZone:            limit.TimeZone,
+	}
 }
 
 func NewJsonParser() ConfigParser {
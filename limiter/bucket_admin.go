@@ -0,0 +1,173 @@
+package limiter
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultListBucketsLimit is how many buckets ListBuckets returns when no
+// limit is given, and MaxListBucketsLimit the most it returns at once.
+const DefaultListBucketsLimit = 100
+const MaxListBucketsLimit = 1000
+
+type ListBucketsRequest struct {
+	// ServiceID and ClientID only keep the buckets of that service or
+	// client when set.
+	ServiceID string
+	ClientID  string
+	// Cursor is the id of the last bucket of the previous page. Buckets are
+	// listed in the order of their ids.
+	Cursor string
+	Limit  int
+}
+
+// ListBuckets returns a page of buckets and the cursor of the next page,
+// which is empty on the last page.
+func (bs *BucketStorageImpl) ListBuckets(body ListBucketsRequest) ([]*Bucket, string) {
+	limit := body.Limit
+	if limit <= 0 {
+		limit = DefaultListBucketsLimit
+	}
+	limit = min(limit, MaxListBucketsLimit)
+
+	bs.mu.RLock()
+	buckets := make([]*Bucket, 0)
+	for _, b := range bs.BucketsMap {
+		if body.Cursor != "" && b.ID <= body.Cursor {
+			continue
+		}
+		if body.ServiceID != "" && b.ServiceID != body.ServiceID {
+			continue
+		}
+		if body.ClientID != "" && b.ClientID != body.ClientID {
+			continue
+		}
+		buckets = append(buckets, b)
+	}
+	bs.mu.RUnlock()
+
+	slices.SortFunc(buckets, func(a, b *Bucket) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(buckets) <= limit {
+		return buckets, ""
+	}
+	buckets = buckets[:limit]
+	return buckets, buckets[limit-1].ID
+}
+
+// ResetBucket fills a bucket up to its MaxTokens, as if nothing had been
+// consumed from it.
+func (bs *BucketStorageImpl) ResetBucket(id string) error {
+	log.Printf("event=reset_bucket bucket_id=%q", id)
+	return bs.SetBucketTokens(id, ^uint64(0))
+}
+
+// SetBucketTokens changes a bucket so that exactly tokens can be consumed
+// from it right now, or MaxTokens if tokens is larger.
+func (bs *BucketStorageImpl) SetBucketTokens(id string, tokens uint64) error {
+	b, err := bs.GetBucket(id)
+	if err != nil {
+		return err
+	}
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+	l, err := b.Limiter()
+	if err != nil {
+		log.Printf("event=get_limiter status=error bucket_id=%q algorithm=%q err=%v", b.ID, b.Algorithm, err)
+		return err
+	}
+	now := time.Now()
+	// Every algorithm can be filled up by refunding its capacity, and then
+	// drained down to the wanted tokens.
+	l.Refund(b.MaxTokens, now)
+	if tokens < b.MaxTokens {
+		l.Consume(b.MaxTokens-tokens, now)
+	}
+	log.Printf("event=set_bucket_tokens bucket_id=%q tokens=%d remaining=%d", b.ID, tokens, l.Check(0, now).Remaining)
+	return nil
+}
+
+// DeleteBucket removes a bucket. The next request limited by it creates it
+// again from the rule matching the request.
+func (bs *BucketStorageImpl) DeleteBucket(id string) error {
+	log.Printf("event=delete_bucket bucket_id=%q", id)
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	b, exists := bs.BucketsMap[id]
+	if !exists {
+		log.Printf("event=delete_bucket status=error bucket_id=%q err=%q", id, ErrBucketNotFound)
+		return ErrBucketNotFound
+	}
+	delete(bs.BucketsMap, id)
+	// Dropping the key makes the next request resolve it again, which
+	// recreates the deleted bucket and keeps the others of the key.
+	delete(bs.bucketsByKey, b.Key)
+	return nil
+}
+
+// ForgetUnmatchedScopes makes client and organization scopes that no rule
+// matched so far look for a matching rule again on their next request. It
+// has to be called after rules change.
+func (bs *BucketStorageImpl) ForgetUnmatchedScopes() {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	for key, buckets := range bs.bucketsByKey {
+		if len(buckets) == 0 {
+			delete(bs.bucketsByKey, key)
+		}
+	}
+}
+
+// MigrateRuleBuckets moves the buckets created for a rule onto its new
+// limits. A bucket whose limit kept its algorithm is changed in place and
+// keeps what was consumed from it; any other bucket of the rule is deleted,
+// so that the next request creates it again from the rule that matches it
+// then. Passing no limits deletes every bucket of the rule, which is what
+// removing the rule calls for. It returns how many buckets were changed and
+// how many were deleted.
+func (bs *BucketStorageImpl) MigrateRuleBuckets(ruleID string, limits []Limit) (migrated int, deleted int) {
+	bs.mu.RLock()
+	buckets := make([]*Bucket, 0)
+	for _, b := range bs.BucketsMap {
+		if b.RuleID == ruleID && b.Level != LevelPool {
+			buckets = append(buckets, b)
+		}
+	}
+	bs.mu.RUnlock()
+
+	now := time.Now()
+	for _, b := range buckets {
+		i := slices.IndexFunc(limits, func(l Limit) bool { return l.Name == b.LimitName })
+		b.Mu.Lock()
+		if i >= 0 && algorithmOf(limits[i].Algorithm) == algorithmOf(b.Algorithm) {
+			migrateBucket(b, limits[i], now)
+			b.Mu.Unlock()
+			migrated++
+			continue
+		}
+		b.Mu.Unlock()
+		if bs.DeleteBucket(b.ID) == nil {
+			deleted++
+		}
+	}
+	log.Printf("event=migrate_rule_buckets rule_id=%q migrated=%d deleted=%d", ruleID, migrated, deleted)
+	return migrated, deleted
+}
+
+// migrateBucket expects b.Mu to be held.
+func migrateBucket(b *Bucket, limit Limit, now time.Time) {
+	if algorithmOf(b.Algorithm) == AlgorithmTokenBucket {
+		// Tokens refilled so far are earned at the old rate
+		refill(b, now)
+		b.Tokens = min(b.Tokens, limit.MaxTokens)
+	}
+	b.RefillRatePerSecond = limit.RefillRatePerSecond
+	b.MaxTokens = limit.MaxTokens
+	b.WindowSeconds = limit.WindowSeconds
+	b.Period = limit.Period
+	b.TimeZone = limit.TimeZone
+	log.Printf("event=migrate_bucket bucket_id=%q refill_rate_per_second=%d max_tokens=%d window_seconds=%d period=%q time_zone=%q", b.ID, b.RefillRatePerSecond, b.MaxTokens, b.WindowSeconds, b.Period, b.TimeZone)
+}
@@ -0,0 +1,94 @@
+package limiter
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidPeriod = errors.New("period should be one of \"day\" or \"month\" for this algorithm")
+
+// Period is the calendar unit a calendar quota resets on.
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+)
+
+// calendarQuota admits at most MaxTokens tokens per calendar day or month in
+// the bucket's TimeZone. Unlike the window algorithms, the quota resets at
+// local midnight or on the first of the month rather than on boundaries
+// counted from the Unix epoch. WindowStart and WindowCount hold the start of
+// the current period and the tokens used in it.
+type calendarQuota struct {
+	b        *Bucket
+	location *time.Location
+}
+
+// bounds returns the start of the period containing now and the start of
+// the next one.
+func (q calendarQuota) bounds(now time.Time) (start, next time.Time) {
+	local := now.In(q.location)
+	year, month, day := local.Date()
+	if q.b.Period == PeriodMonth {
+		start = time.Date(year, month, 1, 0, 0, 0, 0, q.location)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(year, month, day, 0, 0, 0, 0, q.location)
+	return start, start.AddDate(0, 0, 1)
+}
+
+func (q calendarQuota) used(start time.Time) uint64 {
+	if !start.Equal(q.b.WindowStart) {
+		return 0
+	}
+	return q.b.WindowCount
+}
+
+func (q calendarQuota) Check(cost uint64, now time.Time) LimitStatus {
+	start, next := q.bounds(now)
+	used := q.used(start)
+	status := LimitStatus{
+		Remaining: q.b.MaxTokens - min(used, q.b.MaxTokens),
+		Limit:     q.b.MaxTokens,
+	}
+	if used > 0 {
+		status.ResetAfter = next.Sub(now)
+	}
+	if used+cost <= q.b.MaxTokens {
+		status.Allowed = true
+		return status
+	}
+	status.RetryAfter = next.Sub(now)
+	return status
+}
+
+func (q calendarQuota) Refund(amount uint64, now time.Time) {
+	start, _ := q.bounds(now)
+	used := q.used(start)
+	q.b.WindowCount = used - min(amount, used)
+	q.b.WindowStart = start
+}
+
+func (q calendarQuota) Consume(cost uint64, now time.Time) {
+	start, _ := q.bounds(now)
+	q.b.WindowCount = q.used(start) + cost
+	q.b.WindowStart = start
+}
+
+var locations sync.Map
+
+// loadLocation caches time.LoadLocation, which reads the zone database from
+// disk on every call. An empty name is UTC.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
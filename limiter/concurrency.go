@@ -0,0 +1,326 @@
+package limiter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var ErrSemaphoreNotFound = errors.New("semaphore not found")
+var ErrCreateSemaphoreIdCollision = errors.New("a semaphore already exists with this id")
+var ErrInvalidMaxConcurrent = errors.New("max concurrent should be specified with a value > 0 to acquire leases")
+var ErrLeaseNotFound = errors.New("lease not found")
+
+// DefaultLeaseTTL is used for semaphores whose rule does not set a lease TTL.
+const DefaultLeaseTTL = 60 * time.Second
+
+type CreateSemaphoreReqBody struct {
+	ID              string
+	MaxConcurrent   uint64
+	LeaseTTLSeconds uint64
+}
+
+// Semaphore limits how many leases can be held at once for a key. Leases
+// that are not released before they expire are dropped, so a caller that
+// crashes mid-job only holds its slot until the lease TTL runs out.
+type Semaphore struct {
+	ID              string
+	MaxConcurrent   uint64               `json:"max_concurrent"`
+	LeaseTTLSeconds uint64               `json:"lease_ttl_seconds"`
+	Leases          map[string]time.Time `json:"leases"`
+	CreatedAt       time.Time            `json:"created_at"`
+	Mu              sync.Mutex           `json:"-"`
+}
+
+type AcquireRequest struct {
+	ServiceID string
+	ClientID  string
+	UserID    string
+	UserTier  string
+}
+
+type AcquireResponse struct {
+	IsAllowed         bool
+	LeaseID           string
+	ExpiresAt         time.Time
+	RetryAfterSeconds uint64
+}
+
+type ReleaseRequest struct {
+	ServiceID string
+	ClientID  string
+	UserID    string
+	LeaseID   string
+}
+
+type ConcurrencyStorage interface {
+	CreateSemaphore(body CreateSemaphoreReqBody) error
+	RestoreSemaphore(semaphore *Semaphore) error
+	Acquire(body AcquireRequest) (AcquireResponse, error)
+	Release(body ReleaseRequest) error
+	GetAllSemaphores() []*Semaphore
+	GetSemaphore(ID string) (*Semaphore, error)
+}
+
+type ConcurrencyStorageImpl struct {
+	SemaphoresMap map[string]*Semaphore
+	RuleRegistry  RuleRegistry
+	// mu guards SemaphoresMap. Each semaphore's own Mu guards its leases.
+	mu sync.RWMutex
+}
+
+// semaphoreScope is one level of the hierarchy a semaphore can be shared
+// at, like bucketScope is for buckets.
+type semaphoreScope struct {
+	key   string
+	match MatchRuleRequest
+}
+
+// semaphoreScopes returns the user, client and organization scopes of a
+// request, in the order their rules are looked at.
+func (cs *ConcurrencyStorageImpl) semaphoreScopes(serviceID string, clientID string, userID string, userTier string) []semaphoreScope {
+	orgID := cs.RuleRegistry.GetClientOrganization(clientID)
+	scopes := []semaphoreScope{
+		{
+			key: GetBucketID(GetBucketIDRequest{ServiceID: serviceID, ClientID: clientID, UserID: userID}),
+			match: MatchRuleRequest{
+				Level:     LevelUser,
+				ServiceID: serviceID,
+				ClientID:  clientID,
+				OrgID:     orgID,
+				UserID:    userID,
+				UserTier:  userTier,
+			},
+		},
+		{
+			key: GetClientBucketID(serviceID, clientID),
+			match: MatchRuleRequest{
+				Level:     LevelClient,
+				ServiceID: serviceID,
+				ClientID:  clientID,
+				OrgID:     orgID,
+			},
+		},
+	}
+	if orgID != "" {
+		scopes = append(scopes, semaphoreScope{
+			key: GetOrgBucketID(serviceID, orgID),
+			match: MatchRuleRequest{
+				Level:     LevelOrganization,
+				ServiceID: serviceID,
+				OrgID:     orgID,
+			},
+		})
+	}
+	return scopes
+}
+
+// matchSemaphore returns the key of the semaphore limiting the request and
+// the rule its slots come from: the first of the user, client and
+// organization rules that sets MaxConcurrent, or else the rule matching the
+// user.
+func (cs *ConcurrencyStorageImpl) matchSemaphore(body AcquireRequest) (string, Rule) {
+	scopes := cs.semaphoreScopes(body.ServiceID, body.ClientID, body.UserID, body.UserTier)
+	for _, scope := range scopes {
+		rule, found := cs.RuleRegistry.FindRule(scope.match)
+		if found && rule.MaxConcurrent > 0 {
+			return scope.key, rule
+		}
+	}
+	return scopes[0].key, cs.RuleRegistry.MatchRule(scopes[0].match)
+}
+
+func (cs *ConcurrencyStorageImpl) GetSemaphore(id string) (*Semaphore, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	s, exists := cs.SemaphoresMap[id]
+	if !exists {
+		return nil, ErrSemaphoreNotFound
+	}
+	return s, nil
+}
+
+func (cs *ConcurrencyStorageImpl) RestoreSemaphore(semaphore *Semaphore) error {
+	semaphore.Mu.Lock()
+	defer semaphore.Mu.Unlock()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	_, exists := cs.SemaphoresMap[semaphore.ID]
+	if exists {
+		log.Printf("level=warn event=restore_semaphore semaphore already exists")
+		return ErrCreateSemaphoreIdCollision
+	}
+	if semaphore.Leases == nil {
+		semaphore.Leases = make(map[string]time.Time)
+	}
+	cs.SemaphoresMap[semaphore.ID] = semaphore
+	return nil
+}
+
+func (cs *ConcurrencyStorageImpl) CreateSemaphore(body CreateSemaphoreReqBody) error {
+	log.Printf("event=create_semaphore semaphore_id=%q max_concurrent=%d lease_ttl_seconds=%d", body.ID, body.MaxConcurrent, body.LeaseTTLSeconds)
+	if body.MaxConcurrent <= 0 {
+		log.Printf("event=create_semaphore status=error semaphore_id=%q errors=%q", body.ID, ErrInvalidMaxConcurrent)
+		return ErrInvalidMaxConcurrent
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	_, exists := cs.SemaphoresMap[body.ID]
+	if exists {
+		log.Printf("event=create_semaphore status=error errors=%q", ErrCreateSemaphoreIdCollision)
+		return ErrCreateSemaphoreIdCollision
+	}
+	cs.SemaphoresMap[body.ID] = &Semaphore{
+		ID:              body.ID,
+		MaxConcurrent:   body.MaxConcurrent,
+		LeaseTTLSeconds: body.LeaseTTLSeconds,
+		Leases:          make(map[string]time.Time),
+		CreatedAt:       time.Now(),
+	}
+	log.Printf("event=semaphore_created semaphore_id=%q", body.ID)
+	return nil
+}
+
+// Acquire hands out a lease of the semaphore of the request's rule, creating
+// the semaphore on first use. The semaphore takes MaxConcurrent and
+// LeaseTTLSeconds from the rule on every call, so rule changes apply to
+// semaphores that already exist.
+func (cs *ConcurrencyStorageImpl) Acquire(body AcquireRequest) (res AcquireResponse, err error) {
+	semaphoreID, rule := cs.matchSemaphore(body)
+	if rule.MaxConcurrent <= 0 {
+		log.Printf("event=acquire_lease status=error semaphore_id=%q rule_id=%q err=%q", semaphoreID, rule.ID, ErrInvalidMaxConcurrent)
+		return res, ErrInvalidMaxConcurrent
+	}
+	s, err := cs.GetSemaphore(semaphoreID)
+	if err == ErrSemaphoreNotFound {
+		err = cs.CreateSemaphore(CreateSemaphoreReqBody{
+			ID:              semaphoreID,
+			MaxConcurrent:   rule.MaxConcurrent,
+			LeaseTTLSeconds: rule.LeaseTTLSeconds,
+		})
+		if err != nil && err != ErrCreateSemaphoreIdCollision {
+			return res, err
+		}
+		s, err = cs.GetSemaphore(semaphoreID)
+	}
+	if err != nil {
+		return res, err
+	}
+
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+
+	if s.MaxConcurrent != rule.MaxConcurrent || s.LeaseTTLSeconds != rule.LeaseTTLSeconds {
+		log.Printf("event=update_semaphore semaphore_id=%q rule_id=%q max_concurrent=%d lease_ttl_seconds=%d old_max_concurrent=%d old_lease_ttl_seconds=%d", s.ID, rule.ID, rule.MaxConcurrent, rule.LeaseTTLSeconds, s.MaxConcurrent, s.LeaseTTLSeconds)
+		s.MaxConcurrent = rule.MaxConcurrent
+		s.LeaseTTLSeconds = rule.LeaseTTLSeconds
+	}
+	now := time.Now()
+	expireLeases(s, now)
+	if uint64(len(s.Leases)) >= s.MaxConcurrent {
+		res.IsAllowed = false
+		res.RetryAfterSeconds = roundUp(nextLeaseExpiry(s).Sub(now), time.Second)
+		log.Printf("event=acquire_lease status=denied semaphore_id=%q held=%d max_concurrent=%d retry_after=%d", s.ID, len(s.Leases), s.MaxConcurrent, res.RetryAfterSeconds)
+		return res, nil
+	}
+	leaseID, err := newID()
+	if err != nil {
+		log.Printf("event=acquire_lease status=error semaphore_id=%q err=%q", s.ID, err)
+		return res, err
+	}
+	res.IsAllowed = true
+	res.LeaseID = leaseID
+	res.ExpiresAt = now.Add(leaseTTL(s))
+	s.Leases[leaseID] = res.ExpiresAt
+	log.Printf("event=acquire_lease status=success semaphore_id=%q lease_id=%q held=%d max_concurrent=%d", s.ID, leaseID, len(s.Leases), s.MaxConcurrent)
+	return res, nil
+}
+
+// Release gives back a lease. The lease is looked for in the semaphores of
+// every scope of the request, since the rule it was acquired under may have
+// changed since.
+func (cs *ConcurrencyStorageImpl) Release(body ReleaseRequest) error {
+	err := ErrSemaphoreNotFound
+	for _, scope := range cs.semaphoreScopes(body.ServiceID, body.ClientID, body.UserID, "") {
+		s, getErr := cs.GetSemaphore(scope.key)
+		if getErr != nil {
+			continue
+		}
+		err = releaseLease(s, body.LeaseID)
+		if err != ErrLeaseNotFound {
+			return err
+		}
+	}
+	return err
+}
+
+func releaseLease(s *Semaphore, leaseID string) error {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+
+	expireLeases(s, time.Now())
+	_, held := s.Leases[leaseID]
+	if !held {
+		log.Printf("event=release_lease status=error semaphore_id=%q lease_id=%q err=%q", s.ID, leaseID, ErrLeaseNotFound)
+		return ErrLeaseNotFound
+	}
+	delete(s.Leases, leaseID)
+	log.Printf("event=release_lease status=success semaphore_id=%q lease_id=%q held=%d", s.ID, leaseID, len(s.Leases))
+	return nil
+}
+
+func (cs *ConcurrencyStorageImpl) GetAllSemaphores() []*Semaphore {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	semaphores := make([]*Semaphore, 0)
+	for _, s := range cs.SemaphoresMap {
+		semaphores = append(semaphores, s)
+	}
+	return semaphores
+}
+
+func leaseTTL(s *Semaphore) time.Duration {
+	if s.LeaseTTLSeconds <= 0 {
+		return DefaultLeaseTTL
+	}
+	return time.Duration(s.LeaseTTLSeconds) * time.Second
+}
+
+func expireLeases(s *Semaphore, now time.Time) {
+	for id, expiresAt := range s.Leases {
+		if !expiresAt.After(now) {
+			log.Printf("event=lease_expired semaphore_id=%q lease_id=%q", s.ID, id)
+			delete(s.Leases, id)
+		}
+	}
+}
+
+func nextLeaseExpiry(s *Semaphore) time.Time {
+	var next time.Time
+	for _, expiresAt := range s.Leases {
+		if next.IsZero() || expiresAt.Before(next) {
+			next = expiresAt
+		}
+	}
+	return next
+}
+
+func newID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func NewConcurrencyStorage(ruleRegistry RuleRegistry) ConcurrencyStorage {
+	return &ConcurrencyStorageImpl{
+		SemaphoresMap: make(map[string]*Semaphore),
+		RuleRegistry:  ruleRegistry,
+	}
+}
@@ -0,0 +1,103 @@
+package limiter
+
+import (
+	"errors"
+	"testing"
+)
+
+func acquireN(t *testing.T, cs ConcurrencyStorage, req AcquireRequest, n int) (allowed int, leaseIDs []string) {
+	t.Helper()
+	for range n {
+		res, err := cs.Acquire(req)
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		if res.IsAllowed {
+			allowed++
+			leaseIDs = append(leaseIDs, res.LeaseID)
+		}
+	}
+	return allowed, leaseIDs
+}
+
+func TestAcquireClientLevelRule(t *testing.T) {
+	rr := NewRuleRegistry(DefaultRule)
+	err := rr.AddRule(Rule{ID: "jobs", Level: LevelClient, ServiceID: "svc", RefillRatePerSecond: 1, MaxTokens: 10, MaxConcurrent: 2})
+	if err != nil {
+		t.Fatalf("AddRule() error = %v", err)
+	}
+	cs := NewConcurrencyStorage(rr)
+
+	allowed, leaseIDs := acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1"}, 1)
+	more, _ := acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c1", UserID: "u2"}, 2)
+	if allowed+more != 2 {
+		t.Errorf("leases for the users of one client = %d, want 2", allowed+more)
+	}
+	other, _ := acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c2", UserID: "u1"}, 1)
+	if other != 1 {
+		t.Errorf("leases for another client = %d, want 1", other)
+	}
+
+	err = cs.Release(ReleaseRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", LeaseID: leaseIDs[0]})
+	if err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	allowed, _ = acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c1", UserID: "u3"}, 1)
+	if allowed != 1 {
+		t.Errorf("leases after release = %d, want 1", allowed)
+	}
+}
+
+func TestAcquireOrganizationLevelRule(t *testing.T) {
+	rr := NewRuleRegistry(DefaultRule)
+	err := rr.AddRule(Rule{ID: "org", Level: LevelOrganization, OrgID: "acme", RefillRatePerSecond: 1, MaxTokens: 10, MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("AddRule() error = %v", err)
+	}
+	err = rr.SetOrganization("acme", []string{"c1", "c2"})
+	if err != nil {
+		t.Fatalf("SetOrganization() error = %v", err)
+	}
+	cs := NewConcurrencyStorage(rr)
+
+	first, _ := acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1"}, 1)
+	second, _ := acquireN(t, cs, AcquireRequest{ServiceID: "svc", ClientID: "c2", UserID: "u1"}, 1)
+	if first != 1 || second != 0 {
+		t.Errorf("leases for the clients of one organization = %d and %d, want 1 and 0", first, second)
+	}
+}
+
+func TestAcquireFollowsRuleChanges(t *testing.T) {
+	rule := Rule{ID: "jobs", ServiceID: "svc", RefillRatePerSecond: 1, MaxTokens: 10, MaxConcurrent: 1}
+	rr := NewRuleRegistry(DefaultRule)
+	err := rr.AddRule(rule)
+	if err != nil {
+		t.Fatalf("AddRule() error = %v", err)
+	}
+	cs := NewConcurrencyStorage(rr)
+	req := AcquireRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1"}
+
+	allowed, _ := acquireN(t, cs, req, 3)
+	if allowed != 1 {
+		t.Fatalf("leases = %d, want 1", allowed)
+	}
+	rule.MaxConcurrent = 3
+	err = rr.UpdateRule(rule)
+	if err != nil {
+		t.Fatalf("UpdateRule() error = %v", err)
+	}
+	allowed, _ = acquireN(t, cs, req, 3)
+	if allowed != 2 {
+		t.Errorf("leases after raising max concurrent = %d, want 2", allowed)
+	}
+
+	rule.MaxConcurrent = 0
+	err = rr.UpdateRule(rule)
+	if err != nil {
+		t.Fatalf("UpdateRule() error = %v", err)
+	}
+	_, err = cs.Acquire(req)
+	if !errors.Is(err, ErrInvalidMaxConcurrent) {
+		t.Errorf("Acquire() without max concurrent error = %v, want %v", err, ErrInvalidMaxConcurrent)
+	}
+}
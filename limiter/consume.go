@@ -0,0 +1,195 @@
+package limiter
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// charge is the cost a request takes from one bucket.
+type charge struct {
+	bucket *Bucket
+	cost   uint64
+}
+
+// mergeCharges sums the costs taken from the same bucket and orders the
+// result by bucket id, which is the order bucket locks are taken in.
+func mergeCharges(charges []charge) []charge {
+	merged := make([]charge, 0, len(charges))
+	for _, c := range charges {
+		i := slices.IndexFunc(merged, func(m charge) bool { return m.bucket == c.bucket })
+		if i >= 0 {
+			merged[i].cost += c.cost
+			continue
+		}
+		merged = append(merged, c)
+	}
+	slices.SortFunc(merged, func(a, b charge) int {
+		return strings.Compare(a.bucket.ID, b.bucket.ID)
+	})
+	return merged
+}
+
+// consumeAll takes every charge or none of them. All buckets are locked
+// while they are checked, and tokens are only consumed once every bucket has
+// allowed its charge. A denied response carries the longest retry after
+// among the denying buckets, since that is when all of them will fit.
+func consumeAll(charges []charge) (AccessStatusResponse, error) {
+	return evaluateAll(charges, true)
+}
+
+// checkAll reports what consumeAll would answer without changing any
+// bucket.
+func checkAll(charges []charge) (AccessStatusResponse, error) {
+	return evaluateAll(charges, false)
+}
+
+func evaluateAll(charges []charge, consume bool) (AccessStatusResponse, error) {
+	charges = mergeCharges(charges)
+	defer lockAll(charges)()
+	return evaluate(charges, consume, time.Now())
+}
+
+// consumeBatch consumes the charges of every item or of none of them, like
+// consumeAll does for the charges of all items together. It also answers
+// for every item whether it fits on its own, as checked before anything is
+// consumed, so a denied batch tells which items did not fit. Items that fit
+// on their own can still be denied together when they share a bucket.
+func consumeBatch(items [][]charge) (accRes AccessStatusResponse, itemRes []AccessStatusResponse, err error) {
+	all := make([]charge, 0, len(items))
+	for _, item := range items {
+		all = append(all, item...)
+	}
+	all = mergeCharges(all)
+	defer lockAll(all)()
+
+	now := time.Now()
+	itemRes = make([]AccessStatusResponse, len(items))
+	for i, item := range items {
+		itemRes[i], err = evaluate(mergeCharges(item), false, now)
+		if err != nil {
+			return accRes, nil, err
+		}
+	}
+	accRes, err = evaluate(all, true, now)
+	if err != nil || !accRes.IsAllowed {
+		return accRes, itemRes, err
+	}
+	// Report the capacity left after the batch was consumed
+	for i, item := range items {
+		item = mergeCharges(item)
+		limiters := make([]Limiter, len(item))
+		for j, c := range item {
+			limiters[j], err = c.bucket.Limiter()
+			if err != nil {
+				return accRes, nil, err
+			}
+		}
+		summarize(&itemRes[i], item, limiters, now)
+	}
+	return accRes, itemRes, nil
+}
+
+// lockAll locks the buckets of merged charges in order and returns a func
+// unlocking them.
+func lockAll(charges []charge) func() {
+	for _, c := range charges {
+		c.bucket.Mu.Lock()
+	}
+	return func() {
+		for _, c := range charges {
+			c.bucket.Mu.Unlock()
+		}
+	}
+}
+
+// evaluate expects charges to be merged and their buckets to be locked.
+func evaluate(charges []charge, consume bool, now time.Time) (accRes AccessStatusResponse, err error) {
+	limiters := make([]Limiter, len(charges))
+	denied := false
+	exceedsLimit := false
+	deniedRuleID := ""
+	var retryAfter, delay time.Duration
+	for i, c := range charges {
+		limiters[i], err = c.bucket.Limiter()
+		if err != nil {
+			log.Printf("event=get_limiter status=error bucket_id=%q algorithm=%q err=%v", c.bucket.ID, c.bucket.Algorithm, err)
+			return accRes, err
+		}
+		status := limiters[i].Check(c.cost, now)
+		if status.Allowed {
+			delay = max(delay, status.Delay)
+			continue
+		}
+		log.Printf("event=limit_exceeded bucket_id=%q level=%q limit_name=%q algorithm=%q cost=%d retry_after_ms=%d", c.bucket.ID, c.bucket.Level, c.bucket.LimitName, c.bucket.Algorithm, c.cost, status.RetryAfter.Milliseconds())
+		// A bucket that can never hold the cost is named over the others,
+		// since waiting for them does not help
+		never := c.cost > status.Limit
+		if !denied || (never && !exceedsLimit) || (never == exceedsLimit && status.RetryAfter > retryAfter) {
+			retryAfter = status.RetryAfter
+			accRes.DeniedLimit = c.bucket.LimitName
+			accRes.DeniedLevel = c.bucket.Level
+			deniedRuleID = c.bucket.RuleID
+		}
+		denied = true
+		exceedsLimit = exceedsLimit || never
+	}
+
+	if !denied && consume {
+		for i, c := range charges {
+			limiters[i].Consume(c.cost, now)
+		}
+	}
+	summarize(&accRes, charges, limiters, now)
+	if denied {
+		accRes.IsAllowed = false
+		accRes.RetryAfterSeconds = roundUp(retryAfter, time.Second)
+		accRes.RetryAfterMilliseconds = roundUp(retryAfter, time.Millisecond)
+		accRes.RuleID = deniedRuleID
+		accRes.ExceedsLimit = exceedsLimit
+		return accRes, nil
+	}
+	accRes.IsAllowed = true
+	accRes.DelayMilliseconds = roundUp(delay, time.Millisecond)
+	return accRes, nil
+}
+
+// refundAll gives every charge back to its bucket.
+func refundAll(charges []charge) (accRes AccessStatusResponse, err error) {
+	charges = mergeCharges(charges)
+	defer lockAll(charges)()
+
+	now := time.Now()
+	limiters := make([]Limiter, len(charges))
+	for i, c := range charges {
+		limiters[i], err = c.bucket.Limiter()
+		if err != nil {
+			log.Printf("event=get_limiter status=error bucket_id=%q algorithm=%q err=%v", c.bucket.ID, c.bucket.Algorithm, err)
+			return accRes, err
+		}
+	}
+	for i, c := range charges {
+		limiters[i].Refund(c.cost, now)
+		log.Printf("event=refund_tokens bucket_id=%q amount=%d", c.bucket.ID, c.cost)
+	}
+	summarize(&accRes, charges, limiters, now)
+	return accRes, nil
+}
+
+// summarize fills the remaining capacity and rule of the response from the
+// bucket with the fewest tokens left, and the reset time from the bucket
+// that takes longest to be full again.
+func summarize(accRes *AccessStatusResponse, charges []charge, limiters []Limiter, now time.Time) {
+	var resetAfter time.Duration
+	for i, l := range limiters {
+		status := l.Check(0, now)
+		if i == 0 || status.Remaining < accRes.Remaining {
+			accRes.Remaining = status.Remaining
+			accRes.Limit = status.Limit
+			accRes.RuleID = charges[i].bucket.RuleID
+		}
+		resetAfter = max(resetAfter, status.ResetAfter)
+	}
+	accRes.ResetAfterSeconds = roundUp(resetAfter, time.Second)
+}
@@ -0,0 +1,56 @@
+package limiter
+
+import (
+	"time"
+)
+
+// fixedWindow admits at most MaxTokens tokens per window of WindowSeconds.
+// Windows are aligned to the Unix epoch, so a 60 second window resets at the
+// top of every minute and a 3600 second window at the top of every hour.
+type fixedWindow struct {
+	b *Bucket
+}
+
+func (f fixedWindow) window() time.Duration {
+	return time.Duration(f.b.WindowSeconds) * time.Second
+}
+
+// used returns the start of the window containing now and the tokens
+// consumed in it so far.
+func (f fixedWindow) used(now time.Time) (start time.Time, used uint64) {
+	start = windowStart(now, f.window())
+	if !start.Equal(f.b.WindowStart) {
+		return start, 0
+	}
+	return start, f.b.WindowCount
+}
+
+func (f fixedWindow) Check(cost uint64, now time.Time) LimitStatus {
+	start, used := f.used(now)
+	reset := start.Add(f.window()).Sub(now)
+	status := LimitStatus{
+		Remaining: f.b.MaxTokens - min(used, f.b.MaxTokens),
+		Limit:     f.b.MaxTokens,
+	}
+	if used > 0 {
+		status.ResetAfter = reset
+	}
+	if used+cost <= f.b.MaxTokens {
+		status.Allowed = true
+		return status
+	}
+	status.RetryAfter = reset
+	return status
+}
+
+func (f fixedWindow) Refund(amount uint64, now time.Time) {
+	start, used := f.used(now)
+	f.b.WindowStart = start
+	f.b.WindowCount = used - min(amount, used)
+}
+
+func (f fixedWindow) Consume(cost uint64, now time.Time) {
+	start, used := f.used(now)
+	f.b.WindowStart = start
+	f.b.WindowCount = used + cost
+}
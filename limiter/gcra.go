@@ -0,0 +1,62 @@
+package limiter
+
+import (
+	"time"
+)
+
+// gcra implements the generic cell rate algorithm. Instead of counting
+// tokens it stores a single theoretical arrival time (TAT): the time at
+// which the bucket would be full again if nothing else arrived. Every token
+// pushes the TAT one emission interval (1s / RefillRatePerSecond) further,
+// and a request is admitted as long as the TAT it would produce is no more
+// than MaxTokens emission intervals ahead of now. All arithmetic is done in
+// nanoseconds, so frequent requests are refilled exactly.
+type gcra struct {
+	b *Bucket
+}
+
+// interval returns how long the bucket takes to regain cost tokens.
+func (g gcra) interval(cost uint64) time.Duration {
+	return time.Duration(cost) * time.Second / time.Duration(g.b.RefillRatePerSecond)
+}
+
+func (g gcra) nextTAT(cost uint64, now time.Time) time.Time {
+	tat := g.b.TAT
+	if tat.Before(now) {
+		tat = now
+	}
+	return tat.Add(g.interval(cost))
+}
+
+func (g gcra) Check(cost uint64, now time.Time) LimitStatus {
+	status := LimitStatus{Limit: g.b.MaxTokens}
+	if g.b.TAT.After(now) {
+		status.ResetAfter = g.b.TAT.Sub(now)
+	}
+	// Every emission interval the TAT is ahead of now is a token in use.
+	free := g.interval(g.b.MaxTokens) - status.ResetAfter
+	if free > 0 {
+		status.Remaining = uint64(free * time.Duration(g.b.RefillRatePerSecond) / time.Second)
+	}
+	allowAt := g.nextTAT(cost, now).Add(-g.interval(g.b.MaxTokens))
+	if !now.Before(allowAt) {
+		status.Allowed = true
+		return status
+	}
+	status.RetryAfter = allowAt.Sub(now)
+	return status
+}
+
+// Refund moves the TAT back by the refunded tokens, but not before now, which
+// is where an unused bucket's TAT would be.
+func (g gcra) Refund(amount uint64, now time.Time) {
+	tat := g.b.TAT.Add(-g.interval(amount))
+	if tat.Before(now) {
+		tat = now
+	}
+	g.b.TAT = tat
+}
+
+func (g gcra) Consume(cost uint64, now time.Time) {
+	g.b.TAT = g.nextTAT(cost, now)
+}
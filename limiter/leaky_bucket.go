@@ -0,0 +1,62 @@
+package limiter
+
+import (
+	"time"
+)
+
+// leakyBucket works as a queue that drains RefillRatePerSecond tokens per
+// second. An admitted request takes the next free slot at the end of the
+// queue and the caller is told to wait until that slot comes up, so work
+// reaches the protected service at a constant rate no matter how bursty the
+// callers are. Requests are denied once the queue holds MaxTokens tokens.
+// QueueEnd is the time at which everything queued so far has drained.
+type leakyBucket struct {
+	b *Bucket
+}
+
+// drainTime returns how long the queue takes to drain cost tokens.
+func (l leakyBucket) drainTime(cost uint64) time.Duration {
+	return time.Duration(cost) * time.Second / time.Duration(l.b.RefillRatePerSecond)
+}
+
+// queued returns how long the tokens already queued at now take to drain.
+func (l leakyBucket) queued(now time.Time) time.Duration {
+	if l.b.QueueEnd.Before(now) {
+		return 0
+	}
+	return l.b.QueueEnd.Sub(now)
+}
+
+func (l leakyBucket) Check(cost uint64, now time.Time) LimitStatus {
+	queued := l.queued(now)
+	status := LimitStatus{
+		Limit:      l.b.MaxTokens,
+		ResetAfter: queued,
+	}
+	free := l.drainTime(l.b.MaxTokens) - queued
+	if free > 0 {
+		status.Remaining = uint64(free * time.Duration(l.b.RefillRatePerSecond) / time.Second)
+	}
+	overflow := queued + l.drainTime(cost) - l.drainTime(l.b.MaxTokens)
+	if overflow <= 0 {
+		status.Allowed = true
+		status.Delay = queued
+		return status
+	}
+	status.RetryAfter = overflow
+	return status
+}
+
+// Refund takes the refunded tokens out of the queue. Requests already
+// admitted keep the delay they were given.
+func (l leakyBucket) Refund(amount uint64, now time.Time) {
+	queueEnd := l.b.QueueEnd.Add(-l.drainTime(amount))
+	if queueEnd.Before(now) {
+		queueEnd = now
+	}
+	l.b.QueueEnd = queueEnd
+}
+
+func (l leakyBucket) Consume(cost uint64, now time.Time) {
+	l.b.QueueEnd = now.Add(l.queued(now) + l.drainTime(cost))
+}
@@ -0,0 +1,169 @@
+package limiter
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown rate limiting algorithm")
+var ErrInvalidWindow = errors.New("window seconds should be specified with a value > 0 for this algorithm")
+var ErrInvalidRefillRate = errors.New("refill rate per second should be specified with a value > 0 for this algorithm")
+
+// Algorithm selects how a Bucket decides whether to admit a request.
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket refills RefillRatePerSecond tokens every second up
+	// to MaxTokens. It is used when a bucket does not name an algorithm.
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+	// AlgorithmSlidingWindowLog admits at most MaxTokens tokens in any
+	// rolling window of WindowSeconds.
+	AlgorithmSlidingWindowLog Algorithm = "sliding_window_log"
+	// AlgorithmSlidingWindowCounter approximates AlgorithmSlidingWindowLog
+	// with two counters per bucket instead of one timestamp per token.
+	AlgorithmSlidingWindowCounter Algorithm = "sliding_window_counter"
+	// AlgorithmFixedWindow admits at most MaxTokens tokens per window of
+	// WindowSeconds and resets at the start of every window.
+	AlgorithmFixedWindow Algorithm = "fixed_window"
+	// AlgorithmGCRA behaves like AlgorithmTokenBucket with a burst of
+	// MaxTokens, but refills continuously with nanosecond precision and
+	// stores a single timestamp per bucket.
+	AlgorithmGCRA Algorithm = "gcra"
+	// AlgorithmLeakyBucket queues up to MaxTokens tokens and releases them
+	// at a constant RefillRatePerSecond, delaying admitted requests instead
+	// of letting bursts through.
+	AlgorithmLeakyBucket Algorithm = "leaky_bucket"
+	// AlgorithmCalendarQuota admits at most MaxTokens tokens per calendar
+	// Period in TimeZone, resetting at local midnight or on the first of the
+	// month.
+	AlgorithmCalendarQuota Algorithm = "calendar_quota"
+)
+
+// LimitStatus is a Limiter's verdict on a prospective request together with
+// the state of the bucket before the request.
+type LimitStatus struct {
+	Allowed    bool
+	RetryAfter time.Duration
+	// Delay is how long an allowed request has to wait before it may
+	// proceed.
+	Delay time.Duration
+	// Remaining is how many tokens could be consumed right now and Limit
+	// how many the bucket holds when it is full.
+	Remaining uint64
+	Limit     uint64
+	// ResetAfter is how long the bucket takes to be full again if nothing
+	// else is consumed.
+	ResetAfter time.Duration
+}
+
+// ResetAfterSeconds is ResetAfter rounded up to whole seconds, the way
+// every response reports it.
+func (s LimitStatus) ResetAfterSeconds() uint64 {
+	return roundUp(s.ResetAfter, time.Second)
+}
+
+// Limiter is implemented by every algorithm a Bucket can run. The bucket's
+// lock must be held around calls to it.
+type Limiter interface {
+	// Check reports whether cost tokens can be consumed at now. It does not
+	// change the bucket.
+	Check(cost uint64, now time.Time) LimitStatus
+	// Consume takes cost tokens at now. It is only called after Check
+	// allowed the same cost at the same time.
+	Consume(cost uint64, now time.Time)
+	// Refund gives back up to amount tokens consumed earlier, never
+	// leaving the bucket with more than MaxTokens.
+	Refund(amount uint64, now time.Time)
+}
+
+// Limiter returns the algorithm implementation for the bucket.
+func (b *Bucket) Limiter() (Limiter, error) {
+	switch b.Algorithm {
+	case "", AlgorithmTokenBucket:
+		return tokenBucket{b}, nil
+	case AlgorithmSlidingWindowLog:
+		return slidingWindowLog{b}, nil
+	case AlgorithmSlidingWindowCounter:
+		return slidingWindowCounter{b}, nil
+	case AlgorithmFixedWindow:
+		return fixedWindow{b}, nil
+	case AlgorithmGCRA:
+		return gcra{b}, nil
+	case AlgorithmLeakyBucket:
+		return leakyBucket{b}, nil
+	case AlgorithmCalendarQuota:
+		location, err := loadLocation(b.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		return calendarQuota{b, location}, nil
+	}
+	return nil, ErrUnknownAlgorithm
+}
+
+func validateAlgorithm(body CreateBucketReqBody) error {
+	errs := algorithmFieldErrors(Limit{
+		Algorithm:           body.Algorithm,
+		RefillRatePerSecond: body.RefillRatePerSecond,
+		WindowSeconds:       body.WindowSeconds,
+		Period:              body.Period,
+		TimeZone:            body.TimeZone,
+	})
+	if len(errs) > 0 {
+		return errs[0].Err
+	}
+	return nil
+}
+
+// algorithmFieldErrors checks the parameters the algorithm of a limit uses.
+func algorithmFieldErrors(limit Limit) []FieldError {
+	var errs []FieldError
+	switch limit.Algorithm {
+	case "", AlgorithmTokenBucket, AlgorithmGCRA, AlgorithmLeakyBucket:
+		if limit.RefillRatePerSecond <= 0 {
+			errs = append(errs, FieldError{Field: "refill_rate_per_second", Err: ErrInvalidRefillRate})
+		}
+	case AlgorithmSlidingWindowLog, AlgorithmSlidingWindowCounter, AlgorithmFixedWindow:
+		if limit.WindowSeconds <= 0 {
+			errs = append(errs, FieldError{Field: "window_seconds", Err: ErrInvalidWindow})
+		}
+	case AlgorithmCalendarQuota:
+		if limit.Period != PeriodDay && limit.Period != PeriodMonth {
+			errs = append(errs, FieldError{Field: "period", Err: ErrInvalidPeriod})
+		}
+		_, err := loadLocation(limit.TimeZone)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "time_zone", Err: err})
+		}
+	default:
+		errs = append(errs, FieldError{Field: "algorithm", Err: ErrUnknownAlgorithm})
+	}
+	return errs
+}
+
+// algorithmOf treats buckets and limits without an algorithm as token
+// buckets.
+func algorithmOf(algorithm Algorithm) Algorithm {
+	if algorithm == "" {
+		return AlgorithmTokenBucket
+	}
+	return algorithm
+}
+
+// windowStart returns the start of the window of the given length that
+// contains now. Windows are aligned to the Unix epoch; time.Time.Truncate
+// aligns them to the zero time instead, which only agrees for windows that
+// divide a day.
+func windowStart(now time.Time, window time.Duration) time.Time {
+	offset := time.Duration(now.UnixNano()) % window
+	return now.Add(-offset).Round(0)
+}
+
+// roundUp converts d to a whole number of units, rounding up so callers
+// never retry or proceed before capacity is actually available.
+func roundUp(d time.Duration, unit time.Duration) uint64 {
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + unit - 1) / unit)
+}
@@ -0,0 +1,79 @@
+package limiter
+
+import (
+	"errors"
+	"log"
+	"slices"
+)
+
+var ErrPoolNotFound = errors.New("pool not found")
+var ErrCreatePoolIdCollision = errors.New("a pool already exists with this id")
+
+// Pool is a set of services that draw from one shared bucket per client.
+// Every request to one of its services is also charged to the client's pool
+// bucket, at the price of the requested service.
+type Pool struct {
+	ID         string
+	ServiceIDs []string
+	Limits     []Limit
+}
+
+type PoolRegistry interface {
+	CreatePool(pool Pool) (Pool, error)
+	GetPool(id string) (Pool, error)
+	GetServicePools(serviceID string) []Pool
+}
+
+type PoolRegistryImpl struct {
+	poolsMap map[string]*Pool
+	// servicePools maps service ids to the ids of the pools they belong to.
+	servicePools map[string][]string
+}
+
+// CreatePool registers a pool. A service listed more than once is only
+// charged to the pool once.
+func (pr *PoolRegistryImpl) CreatePool(pool Pool) (Pool, error) {
+	log.Printf("action=create_pool id=%q service_ids=%q limits=%d", pool.ID, pool.ServiceIDs, len(pool.Limits))
+	_, exists := pr.poolsMap[pool.ID]
+	if exists {
+		log.Printf("action=create_pool id=%q error=%q", pool.ID, ErrCreatePoolIdCollision)
+		return Pool{}, ErrCreatePoolIdCollision
+	}
+	serviceIDs := make([]string, 0, len(pool.ServiceIDs))
+	for _, serviceID := range pool.ServiceIDs {
+		if !slices.Contains(serviceIDs, serviceID) {
+			serviceIDs = append(serviceIDs, serviceID)
+		}
+	}
+	pool.ServiceIDs = serviceIDs
+	pr.poolsMap[pool.ID] = &pool
+	for _, serviceID := range pool.ServiceIDs {
+		pr.servicePools[serviceID] = append(pr.servicePools[serviceID], pool.ID)
+	}
+	return pool, nil
+}
+
+func (pr *PoolRegistryImpl) GetPool(id string) (Pool, error) {
+	p, exists := pr.poolsMap[id]
+	if !exists {
+		log.Printf("action=get_pool id=%q error=%q", id, ErrPoolNotFound)
+		return Pool{}, ErrPoolNotFound
+	}
+	return *p, nil
+}
+
+// GetServicePools returns every pool the service draws from.
+func (pr *PoolRegistryImpl) GetServicePools(serviceID string) []Pool {
+	pools := make([]Pool, 0, len(pr.servicePools[serviceID]))
+	for _, id := range pr.servicePools[serviceID] {
+		pools = append(pools, *pr.poolsMap[id])
+	}
+	return pools
+}
+
+func NewPoolRegistry() PoolRegistry {
+	return &PoolRegistryImpl{
+		poolsMap:     make(map[string]*Pool),
+		servicePools: make(map[string][]string),
+	}
+}
@@ -0,0 +1,54 @@
+package limiter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreatePool(t *testing.T) {
+	pr := NewPoolRegistry()
+	limits := []Limit{{RefillRatePerSecond: 1, InitialTokens: 10, MaxTokens: 10}}
+	pool, err := pr.CreatePool(Pool{ID: "p", ServiceIDs: []string{"a", "b", "a"}, Limits: limits})
+	if err != nil {
+		t.Fatalf("CreatePool() error = %v", err)
+	}
+	if len(pool.ServiceIDs) != 2 {
+		t.Errorf("CreatePool() service ids = %q, want [a b]", pool.ServiceIDs)
+	}
+	if pools := pr.GetServicePools("a"); len(pools) != 1 {
+		t.Errorf("GetServicePools(a) = %d pools, want 1", len(pools))
+	}
+
+	_, err = pr.CreatePool(Pool{ID: "p", ServiceIDs: []string{"a"}, Limits: limits})
+	if !errors.Is(err, ErrCreatePoolIdCollision) {
+		t.Errorf("CreatePool() with a used id error = %v, want %v", err, ErrCreatePoolIdCollision)
+	}
+	if pools := pr.GetServicePools("a"); len(pools) != 1 {
+		t.Errorf("GetServicePools(a) after collision = %d pools, want 1", len(pools))
+	}
+}
+
+func TestPoolChargedOncePerRequest(t *testing.T) {
+	sr := NewServiceRegistry()
+	_, err := sr.CreateService(CreateServiceReqBody{ID: "svc", UsagePriceInTokens: 1})
+	if err != nil {
+		t.Fatalf("CreateService() error = %v", err)
+	}
+	pr := NewPoolRegistry()
+	_, err = pr.CreatePool(Pool{ID: "p", ServiceIDs: []string{"svc", "svc"}, Limits: []Limit{{RefillRatePerSecond: 1, InitialTokens: 10, MaxTokens: 10}}})
+	if err != nil {
+		t.Fatalf("CreatePool() error = %v", err)
+	}
+	bs := NewBucketStorage(sr, NewRuleRegistry(DefaultRule), pr)
+	_, err = bs.ConsumeService(ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UsageAmount: 1})
+	if err != nil {
+		t.Fatalf("ConsumeService() error = %v", err)
+	}
+	b, err := bs.GetBucket(GetPoolBucketID("p", "c1"))
+	if err != nil {
+		t.Fatalf("GetBucket() error = %v", err)
+	}
+	if b.Tokens != 9 {
+		t.Errorf("pool bucket tokens = %d, want 9", b.Tokens)
+	}
+}
@@ -0,0 +1,107 @@
+package limiter
+
+import (
+	"log"
+	"time"
+)
+
+// RefundKeyTTL is how long a refund's idempotency key is remembered. A
+// refund retried within it is not applied again.
+const RefundKeyTTL = 24 * time.Hour
+
+type RefundServiceRequest struct {
+	ServiceID   string
+	ClientID    string
+	UserID      string
+	UserTier    string
+	UsageAmount uint64
+	// IdempotencyKey identifies the refund. Refunds with the same key for the
+	// same service, client and user are only applied once. Refunds without a
+	// key are always applied.
+	IdempotencyKey string
+}
+
+type RefundResponse struct {
+	// Refunded is false when the refund had already been applied.
+	Refunded bool
+	// Remaining and Limit describe the bucket with the fewest tokens left
+	// after the refund.
+	Remaining uint64
+	Limit     uint64
+}
+
+// RefundService gives back tokens consumed by an earlier ConsumeService call
+// with the same service, client, user and usage amount. Buckets never get
+// more than their MaxTokens back.
+func (bs *BucketStorageImpl) RefundService(body RefundServiceRequest) (refRes RefundResponse, err error) {
+	log.Printf("event=refund_service status=started client_id=%s user_id=%s idempotency_key=%q", body.ClientID, body.UserID, body.IdempotencyKey)
+	req := ConsumeServiceRequest{
+		ServiceID:   body.ServiceID,
+		ClientID:    body.ClientID,
+		UserID:      body.UserID,
+		UserTier:    body.UserTier,
+		UsageAmount: body.UsageAmount,
+	}
+	charges, err := bs.chargesFor(req, false)
+	if err != nil {
+		return
+	}
+
+	refundKey := ""
+	if body.IdempotencyKey != "" {
+		refundKey = GetBucketID(GetBucketIDRequest{ServiceID: body.ServiceID, ClientID: body.ClientID, UserID: body.UserID}) + "#" + body.IdempotencyKey
+		if !bs.claimRefundKey(refundKey, time.Now()) {
+			log.Printf("event=refund_service status=duplicate client_id=%s service_id=%s user_id=%s idempotency_key=%q", body.ClientID, body.ServiceID, body.UserID, body.IdempotencyKey)
+			return
+		}
+	}
+
+	accRes, err := refundAll(charges)
+	if err != nil {
+		if refundKey != "" {
+			bs.releaseRefundKey(refundKey)
+		}
+		return
+	}
+	refRes = RefundResponse{Refunded: true, Remaining: accRes.Remaining, Limit: accRes.Limit}
+	log.Printf("event=refund_service status=success client_id=%s service_id=%s user_id=%s usage_amount=%d remaining=%d", body.ClientID, body.ServiceID, body.UserID, body.UsageAmount, refRes.Remaining)
+	return
+}
+
+// claimedRefundKey is an idempotency key together with when it was claimed.
+type claimedRefundKey struct {
+	key    string
+	seenAt time.Time
+}
+
+// claimRefundKey records the key and reports whether it was not already
+// recorded within RefundKeyTTL. Expired keys are forgotten on the way.
+func (bs *BucketStorageImpl) claimRefundKey(key string, now time.Time) bool {
+	bs.refundsMu.Lock()
+	defer bs.refundsMu.Unlock()
+	expired := 0
+	for _, k := range bs.refundKeys {
+		if now.Sub(k.seenAt) < RefundKeyTTL {
+			break
+		}
+		// A released key may have been claimed again since
+		if bs.refunds[k.key].Equal(k.seenAt) {
+			delete(bs.refunds, k.key)
+		}
+		expired++
+	}
+	bs.refundKeys = bs.refundKeys[expired:]
+	if _, seen := bs.refunds[key]; seen {
+		return false
+	}
+	bs.refunds[key] = now
+	bs.refundKeys = append(bs.refundKeys, claimedRefundKey{key: key, seenAt: now})
+	return true
+}
+
+// releaseRefundKey forgets a key whose refund failed so it can be retried.
+func (bs *BucketStorageImpl) releaseRefundKey(key string) {
+	bs.refundsMu.Lock()
+	defer bs.refundsMu.Unlock()
+	delete(bs.refunds, key)
+}
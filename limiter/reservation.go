@@ -0,0 +1,246 @@
+package limiter
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+var ErrReservationNotFound = errors.New("reservation not found")
+var ErrReservationExpired = errors.New("reservation expired")
+var ErrCreateReservationIdCollision = errors.New("a reservation already exists with this id")
+var ErrCommitExceedsReservation = errors.New("committed usage exceeds the reserved usage")
+
+// DefaultReservationTTL is used for reservations that do not ask for a TTL.
+const DefaultReservationTTL = 5 * time.Minute
+
+// Reservation holds tokens taken from every bucket of a request until the
+// actual usage is committed or the reservation is cancelled. Reservations
+// that are neither committed nor cancelled before they expire are
+// cancelled by ReleaseExpiredReservations.
+type Reservation struct {
+	ID         string
+	ServiceID  string    `json:"service_id"`
+	ClientID   string    `json:"client_id"`
+	UserID     string    `json:"user_id"`
+	BucketIDs  []string  `json:"bucket_ids"`
+	Tokens     uint64    `json:"tokens"`
+	UsagePrice uint64    `json:"usage_price"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type ReserveRequest struct {
+	ServiceID   string
+	ClientID    string
+	UserID      string
+	UserTier    string
+	UsageAmount uint64
+	TTLSeconds  uint64
+}
+
+type ReserveResponse struct {
+	IsAllowed         bool
+	RetryAfterSeconds uint64
+	DeniedLimit       string
+	DeniedLevel       Level
+	ReservationID     string
+	ExpiresAt         time.Time
+}
+
+type CommitRequest struct {
+	ReservationID string
+	// UsageAmount is the usage actually spent. The tokens reserved for the
+	// rest of the reserved usage are refunded.
+	UsageAmount uint64
+}
+
+type CommitResponse struct {
+	// Remaining and Limit describe the bucket with the fewest tokens left
+	// after the commit.
+	Remaining uint64
+	Limit     uint64
+}
+
+// Reserve takes the tokens of the request like ConsumeService does, and
+// keeps them as a reservation until Commit or Cancel is called with its id.
+func (bs *BucketStorageImpl) Reserve(body ReserveRequest) (res ReserveResponse, err error) {
+	log.Printf("event=reserve status=started client_id=%s user_id=%s usage_amount=%d", body.ClientID, body.UserID, body.UsageAmount)
+	service, err := bs.ServiceRegistry.GetService(body.ServiceID)
+	if err != nil {
+		log.Printf("error=service_not_found client_id=%q service_id=%q err=%v", body.ClientID, body.ServiceID, err)
+		return
+	}
+	charges, err := bs.chargesFor(ConsumeServiceRequest{
+		ServiceID:   body.ServiceID,
+		ClientID:    body.ClientID,
+		UserID:      body.UserID,
+		UserTier:    body.UserTier,
+		UsageAmount: body.UsageAmount,
+	}, true)
+	if err != nil {
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		log.Printf("event=reserve status=error client_id=%s service_id=%s err=%q", body.ClientID, body.ServiceID, err)
+		return
+	}
+
+	accRes, err := consumeAll(charges)
+	if err != nil {
+		return
+	}
+	res.IsAllowed = accRes.IsAllowed
+	res.RetryAfterSeconds = accRes.RetryAfterSeconds
+	res.DeniedLimit = accRes.DeniedLimit
+	res.DeniedLevel = accRes.DeniedLevel
+	if !accRes.IsAllowed {
+		log.Printf("event=reserve status=denied service_id=%s client_id=%s user_id=%s denied_level=%q denied_limit=%q retry_after=%d", body.ServiceID, body.ClientID, body.UserID, accRes.DeniedLevel, accRes.DeniedLimit, accRes.RetryAfterSeconds)
+		return
+	}
+
+	ttl := DefaultReservationTTL
+	if body.TTLSeconds > 0 {
+		ttl = time.Duration(body.TTLSeconds) * time.Second
+	}
+	now := time.Now()
+	r := &Reservation{
+		ID:         id,
+		ServiceID:  body.ServiceID,
+		ClientID:   body.ClientID,
+		UserID:     body.UserID,
+		BucketIDs:  make([]string, 0, len(charges)),
+		Tokens:     service.UsagePriceInTokens * body.UsageAmount,
+		UsagePrice: service.UsagePriceInTokens,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	for _, c := range charges {
+		r.BucketIDs = append(r.BucketIDs, c.bucket.ID)
+	}
+	bs.reservationsMu.Lock()
+	bs.reservations[r.ID] = r
+	bs.reservationsMu.Unlock()
+
+	res.ReservationID = r.ID
+	res.ExpiresAt = r.ExpiresAt
+	log.Printf("event=reserve status=success service_id=%s client_id=%s user_id=%s reservation_id=%q tokens=%d expires_at=%q", body.ServiceID, body.ClientID, body.UserID, r.ID, r.Tokens, r.ExpiresAt)
+	return
+}
+
+// Commit settles a reservation at the usage actually spent and refunds the
+// difference to its buckets. The usage cannot exceed the reserved usage.
+func (bs *BucketStorageImpl) Commit(body CommitRequest) (comRes CommitResponse, err error) {
+	bs.reservationsMu.Lock()
+	r, exists := bs.reservations[body.ReservationID]
+	if !exists {
+		bs.reservationsMu.Unlock()
+		log.Printf("event=commit_reservation status=error reservation_id=%q err=%q", body.ReservationID, ErrReservationNotFound)
+		return comRes, ErrReservationNotFound
+	}
+	if r.UsagePrice*body.UsageAmount > r.Tokens {
+		bs.reservationsMu.Unlock()
+		log.Printf("event=commit_reservation status=error reservation_id=%q usage_amount=%d tokens=%d err=%q", r.ID, body.UsageAmount, r.Tokens, ErrCommitExceedsReservation)
+		return comRes, ErrCommitExceedsReservation
+	}
+	delete(bs.reservations, r.ID)
+	bs.reservationsMu.Unlock()
+
+	if !r.ExpiresAt.After(time.Now()) {
+		log.Printf("event=commit_reservation status=error reservation_id=%q err=%q", r.ID, ErrReservationExpired)
+		_, err = bs.refundReservation(r, r.Tokens)
+		if err != nil {
+			return
+		}
+		return comRes, ErrReservationExpired
+	}
+	accRes, err := bs.refundReservation(r, r.Tokens-r.UsagePrice*body.UsageAmount)
+	if err != nil {
+		return
+	}
+	comRes = CommitResponse{Remaining: accRes.Remaining, Limit: accRes.Limit}
+	log.Printf("event=commit_reservation status=success reservation_id=%q usage_amount=%d remaining=%d", r.ID, body.UsageAmount, comRes.Remaining)
+	return
+}
+
+// Cancel releases every token held by a reservation.
+func (bs *BucketStorageImpl) Cancel(reservationID string) error {
+	bs.reservationsMu.Lock()
+	r, exists := bs.reservations[reservationID]
+	delete(bs.reservations, reservationID)
+	bs.reservationsMu.Unlock()
+	if !exists {
+		log.Printf("event=cancel_reservation status=error reservation_id=%q err=%q", reservationID, ErrReservationNotFound)
+		return ErrReservationNotFound
+	}
+	_, err := bs.refundReservation(r, r.Tokens)
+	if err != nil {
+		return err
+	}
+	log.Printf("event=cancel_reservation status=success reservation_id=%q tokens=%d", r.ID, r.Tokens)
+	return nil
+}
+
+// ReleaseExpiredReservations cancels every reservation that expired by now
+// and returns how many there were.
+func (bs *BucketStorageImpl) ReleaseExpiredReservations(now time.Time) int {
+	bs.reservationsMu.Lock()
+	expired := make([]*Reservation, 0)
+	for id, r := range bs.reservations {
+		if !r.ExpiresAt.After(now) {
+			expired = append(expired, r)
+			delete(bs.reservations, id)
+		}
+	}
+	bs.reservationsMu.Unlock()
+
+	for _, r := range expired {
+		log.Printf("event=reservation_expired reservation_id=%q tokens=%d", r.ID, r.Tokens)
+		_, err := bs.refundReservation(r, r.Tokens)
+		if err != nil {
+			log.Printf("event=reservation_expired status=error reservation_id=%q err=%q", r.ID, err)
+		}
+	}
+	return len(expired)
+}
+
+// refundReservation gives amount tokens back to every bucket the
+// reservation was taken from. Buckets that no longer exist are skipped.
+func (bs *BucketStorageImpl) refundReservation(r *Reservation, amount uint64) (AccessStatusResponse, error) {
+	charges := make([]charge, 0, len(r.BucketIDs))
+	for _, id := range r.BucketIDs {
+		b, err := bs.GetBucket(id)
+		if err != nil {
+			log.Printf("level=warn event=refund_reservation reservation_id=%q bucket_id=%q err=%q", r.ID, id, err)
+			continue
+		}
+		charges = append(charges, charge{bucket: b, cost: amount})
+	}
+	return refundAll(charges)
+}
+
+// RestoreReservation adds a persisted reservation. Its buckets have to be
+// restored first.
+func (bs *BucketStorageImpl) RestoreReservation(reservation *Reservation) error {
+	bs.reservationsMu.Lock()
+	defer bs.reservationsMu.Unlock()
+	_, exists := bs.reservations[reservation.ID]
+	if exists {
+		log.Printf("level=warn event=restore_reservation reservation already exists")
+		return ErrCreateReservationIdCollision
+	}
+	bs.reservations[reservation.ID] = reservation
+	return nil
+}
+
+// GetAllReservations returns copies of the open reservations.
+func (bs *BucketStorageImpl) GetAllReservations() []Reservation {
+	bs.reservationsMu.Lock()
+	defer bs.reservationsMu.Unlock()
+	reservations := make([]Reservation, 0, len(bs.reservations))
+	for _, r := range bs.reservations {
+		reservations = append(reservations, *r)
+	}
+	return reservations
+}
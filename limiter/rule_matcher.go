@@ -0,0 +1,98 @@
+package limiter
+
+import (
+	"math"
+	"strings"
+)
+
+// Wildcard matches any value when used in one of a rule's match fields.
+// An empty field behaves the same way.
+const Wildcard = "*"
+
+const exactMatchSpecificity = math.MaxInt
+
+// IsPattern reports whether id is a wildcard or prefix pattern rather than a
+// literal id.
+func IsPattern(id string) bool {
+	return id == "" || strings.HasSuffix(id, Wildcard)
+}
+
+// matchPattern reports whether pattern matches value and how specific the
+// match is. Exact ids beat prefixes, longer prefixes beat shorter ones and
+// a bare wildcard is the least specific match of all.
+func matchPattern(pattern, value string) (specificity int, ok bool) {
+	if pattern == "" || pattern == Wildcard {
+		return 0, true
+	}
+	if prefix, isPrefix := strings.CutSuffix(pattern, Wildcard); isPrefix {
+		if !strings.HasPrefix(value, prefix) {
+			return 0, false
+		}
+		return 1 + len(prefix), true
+	}
+	if pattern != value {
+		return 0, false
+	}
+	return exactMatchSpecificity, true
+}
+
+// ruleSpecificity is compared field by field, in declaration order, to pick
+// the winning rule when several match the same request.
+type ruleSpecificity struct {
+	user     int
+	userTier int
+	client   int
+	org      int
+	service  int
+}
+
+func (a ruleSpecificity) moreSpecificThan(b ruleSpecificity) bool {
+	if a.user != b.user {
+		return a.user > b.user
+	}
+	if a.userTier != b.userTier {
+		return a.userTier > b.userTier
+	}
+	if a.client != b.client {
+		return a.client > b.client
+	}
+	if a.org != b.org {
+		return a.org > b.org
+	}
+	return a.service > b.service
+}
+
+// levelOf treats rules and requests without a level as user level.
+func levelOf(level Level) Level {
+	if level == "" {
+		return LevelUser
+	}
+	return level
+}
+
+func matchRule(rule Rule, req MatchRuleRequest) (ruleSpecificity, bool) {
+	if levelOf(rule.Level) != levelOf(req.Level) {
+		return ruleSpecificity{}, false
+	}
+	user, ok := matchPattern(rule.UserID, req.UserID)
+	if !ok {
+		return ruleSpecificity{}, false
+	}
+	userTier, ok := matchPattern(rule.UserTier, req.UserTier)
+	if !ok {
+		return ruleSpecificity{}, false
+	}
+	client, ok := matchPattern(rule.ClientID, req.ClientID)
+	if !ok {
+		return ruleSpecificity{}, false
+	}
+	org, ok := matchPattern(rule.OrgID, req.OrgID)
+	if !ok {
+		return ruleSpecificity{}, false
+	}
+	service, ok := matchPattern(rule.ServiceID, req.ServiceID)
+	if !ok {
+		return ruleSpecificity{}, false
+	}
+	return ruleSpecificity{user: user, userTier: userTier, client: client, org: org, service: service}, true
+}
@@ -0,0 +1,53 @@
+package limiter
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name            string
+		pattern         string
+		value           string
+		wantOk          bool
+		wantSpecificity int
+	}{
+		{name: "empty matches anything", pattern: "", value: "svc", wantOk: true, wantSpecificity: 0},
+		{name: "empty matches empty", pattern: "", value: "", wantOk: true, wantSpecificity: 0},
+		{name: "wildcard matches anything", pattern: "*", value: "svc", wantOk: true, wantSpecificity: 0},
+		{name: "wildcard matches empty", pattern: "*", value: "", wantOk: true, wantSpecificity: 0},
+		{name: "exact match", pattern: "svc", value: "svc", wantOk: true, wantSpecificity: exactMatchSpecificity},
+		{name: "exact mismatch", pattern: "svc", value: "svc2", wantOk: false},
+		{name: "exact does not match empty", pattern: "svc", value: "", wantOk: false},
+		{name: "prefix match", pattern: "mobile_*", value: "mobile_ios", wantOk: true, wantSpecificity: 1 + len("mobile_")},
+		{name: "prefix matches the bare prefix", pattern: "mobile_*", value: "mobile_", wantOk: true, wantSpecificity: 1 + len("mobile_")},
+		{name: "prefix mismatch", pattern: "mobile_*", value: "web_chrome", wantOk: false},
+		{name: "prefix does not match shorter value", pattern: "mobile_*", value: "mobile", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specificity, ok := matchPattern(tt.pattern, tt.value)
+			if ok != tt.wantOk {
+				t.Fatalf("matchPattern(%q, %q) ok = %v, want %v", tt.pattern, tt.value, ok, tt.wantOk)
+			}
+			if ok && specificity != tt.wantSpecificity {
+				t.Errorf("matchPattern(%q, %q) specificity = %d, want %d", tt.pattern, tt.value, specificity, tt.wantSpecificity)
+			}
+		})
+	}
+}
+
+func TestMatchPatternOrdering(t *testing.T) {
+	// Exact beats a long prefix, which beats a short prefix, which beats a
+	// wildcard
+	patterns := []string{"mobile_ios", "mobile_i*", "mobile_*", "*"}
+	previous := -1
+	for i := len(patterns) - 1; i >= 0; i-- {
+		specificity, ok := matchPattern(patterns[i], "mobile_ios")
+		if !ok {
+			t.Fatalf("matchPattern(%q, %q) did not match", patterns[i], "mobile_ios")
+		}
+		if specificity <= previous {
+			t.Errorf("matchPattern(%q) specificity = %d, want more than %d", patterns[i], specificity, previous)
+		}
+		previous = specificity
+	}
+}
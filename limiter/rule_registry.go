@@ -0,0 +1,370 @@
+package limiter
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+)
+
+var ErrRuleNotFound = errors.New("rule not found")
+var ErrCreateRuleIdCollision = errors.New("a rule already exists with this id")
+var ErrInvalidLevel = errors.New("level should be one of user, client or organization")
+var ErrInvalidMaxTokens = errors.New("max tokens should be specified with a value > 0")
+var ErrDuplicateLimitName = errors.New("limit names should be unique within a rule or pool")
+var ErrInitialExceedsMax = errors.New("initial tokens should not exceed max tokens")
+
+// Level is the part of the organization → client → user hierarchy a rule
+// limits.
+type Level string
+
+const (
+	// LevelUser rules limit every user of a client on their own. Rules
+	// without a level are user level rules.
+	LevelUser Level = "user"
+	// LevelClient rules limit all users of a client together.
+	LevelClient Level = "client"
+	// LevelOrganization rules limit all clients of an organization together.
+	LevelOrganization Level = "organization"
+	// LevelPool marks the buckets of pools shared by several services. Rules
+	// cannot target it.
+	LevelPool Level = "pool"
+)
+
+// Rule holds the bucket parameters applied to every request it matches.
+// ServiceID, ClientID, OrgID, UserID and UserTier are either literal values,
+// a prefix ending in "*" such as "mobile_ios_*", or "*" (or empty) to match
+// anything.
+type Rule struct {
+	ID                  string    `json:"id"`
+	Level               Level     `json:"level,omitempty"`
+	ServiceID           string    `json:"service_id,omitempty"`
+	ClientID            string    `json:"client_id,omitempty"`
+	OrgID               string    `json:"org_id,omitempty"`
+	UserID              string    `json:"user_id,omitempty"`
+	UserTier            string    `json:"user_tier,omitempty"`
+	Algorithm           Algorithm `json:"algorithm,omitempty"`
+	RefillRatePerSecond uint64    `json:"refill_rate_per_second"`
+	InitialTokens       uint64    `json:"initial_tokens"`
+	MaxTokens           uint64    `json:"max_tokens"`
+	WindowSeconds       uint64    `json:"window_seconds,omitempty"`
+	Period              Period    `json:"period,omitempty"`
+	TimeZone            string    `json:"time_zone,omitempty"`
+	MaxConcurrent       uint64    `json:"max_concurrent,omitempty"`
+	LeaseTTLSeconds     uint64    `json:"lease_ttl_seconds,omitempty"`
+	// Limits lists several limits that are all enforced for every request
+	// the rule matches. When it is empty the rule's own Algorithm,
+	// RefillRatePerSecond, InitialTokens, MaxTokens, WindowSeconds, Period
+	// and TimeZone form its single limit.
+	Limits []Limit `json:"limits,omitempty"`
+}
+
+// Limit is one of the limits enforced by a rule. Every limit gets its own
+// bucket, and a request is only admitted if all of them have capacity.
+type Limit struct {
+	Name                string    `json:"name,omitempty"`
+	Algorithm           Algorithm `json:"algorithm,omitempty"`
+	RefillRatePerSecond uint64    `json:"refill_rate_per_second"`
+	InitialTokens       uint64    `json:"initial_tokens"`
+	MaxTokens           uint64    `json:"max_tokens"`
+	WindowSeconds       uint64    `json:"window_seconds,omitempty"`
+	Period              Period    `json:"period,omitempty"`
+	TimeZone            string    `json:"time_zone,omitempty"`
+}
+
+// BucketLimits returns the limits a bucket is created for when the rule
+// matches a request.
+func (r Rule) BucketLimits() []Limit {
+	if len(r.Limits) > 0 {
+		return r.Limits
+	}
+	return []Limit{{
+		Algorithm:           r.Algorithm,
+		RefillRatePerSecond: r.RefillRatePerSecond,
+		InitialTokens:       r.InitialTokens,
+		MaxTokens:           r.MaxTokens,
+		WindowSeconds:       r.WindowSeconds,
+		Period:              r.Period,
+		TimeZone:            r.TimeZone,
+	}}
+}
+
+// ValidateRule reports the first problem that would keep buckets from being
+// created for the rule.
+func ValidateRule(rule Rule) error {
+	errs := RuleFieldErrors(rule)
+	if len(errs) > 0 {
+		return errs[0].Err
+	}
+	return nil
+}
+
+// FieldError is a problem with one field of a rule or limit. Field is named
+// like in the config file, e.g. "limits[1].max_tokens".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// RuleFieldErrors reports every problem that would keep buckets from being
+// created for the rule, in the order ValidateRule checks them.
+func RuleFieldErrors(rule Rule) []FieldError {
+	var errs []FieldError
+	switch rule.Level {
+	case "", LevelUser, LevelClient, LevelOrganization:
+	default:
+		errs = append(errs, FieldError{Field: "level", Err: ErrInvalidLevel})
+	}
+	if len(rule.Limits) == 0 {
+		return append(errs, LimitFieldErrors("", rule.BucketLimits()[0])...)
+	}
+	return append(errs, LimitsFieldErrors(rule.Limits)...)
+}
+
+// LimitsFieldErrors reports the problems of the limits of a rule or pool that
+// has several of them, including names used by more than one limit.
+func LimitsFieldErrors(limits []Limit) []FieldError {
+	var errs []FieldError
+	names := make([]string, 0, len(limits))
+	for j, limit := range limits {
+		prefix := fmt.Sprintf("limits[%d].", j)
+		if slices.Contains(names, limit.Name) {
+			errs = append(errs, FieldError{Field: prefix + "name", Err: ErrDuplicateLimitName})
+		}
+		names = append(names, limit.Name)
+		errs = append(errs, LimitFieldErrors(prefix, limit)...)
+	}
+	return errs
+}
+
+// LimitFieldErrors reports the problems of a single limit. prefix is
+// prepended to the names of the fields.
+func LimitFieldErrors(prefix string, limit Limit) []FieldError {
+	var errs []FieldError
+	if limit.MaxTokens <= 0 {
+		errs = append(errs, FieldError{Field: "max_tokens", Err: ErrInvalidMaxTokens})
+	}
+	// Only token buckets start with InitialTokens
+	if algorithmOf(limit.Algorithm) == AlgorithmTokenBucket && limit.InitialTokens > limit.MaxTokens {
+		errs = append(errs, FieldError{Field: "initial_tokens", Err: ErrInitialExceedsMax})
+	}
+	errs = append(errs, algorithmFieldErrors(limit)...)
+	for i := range errs {
+		errs[i].Field = prefix + errs[i].Field
+	}
+	return errs
+}
+
+// RuleSet is the version of a registry's rules together with the rules.
+// Every change to the rules increments the version.
+type RuleSet struct {
+	Version uint64 `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// DefaultRule is used when a request matches none of the registered rules
+// and no other default was given to NewRuleRegistry.
+var DefaultRule = Rule{
+	ID:                  "default",
+	RefillRatePerSecond: 1,
+	InitialTokens:       100,
+	MaxTokens:           100,
+}
+
+type MatchRuleRequest struct {
+	Level     Level
+	ServiceID string
+	ClientID  string
+	OrgID     string
+	UserID    string
+	UserTier  string
+}
+
+type RuleRegistry interface {
+	AddRule(rule Rule) error
+	UpdateRule(rule Rule) error
+	RemoveRule(id string) error
+	GetRule(id string) (Rule, error)
+	GetDefaultRule() Rule
+	GetRuleSet() RuleSet
+	RestoreRuleSet(ruleSet RuleSet)
+	ReplaceRules(rules []Rule, defaultRule Rule) uint64
+	MatchRule(req MatchRuleRequest) Rule
+	FindRule(req MatchRuleRequest) (Rule, bool)
+	SetOrganization(orgID string, clientIDs []string) error
+	GetClientOrganization(clientID string) string
+}
+
+type RuleRegistryImpl struct {
+	rules       []Rule
+	version     uint64
+	defaultRule Rule
+	// clientOrgs maps client ids to the organization they belong to.
+	clientOrgs map[string]string
+	mu         sync.RWMutex
+}
+
+func (rr *RuleRegistryImpl) AddRule(rule Rule) error {
+	log.Printf("action=add_rule id=%q level=%q service_id=%q client_id=%q org_id=%q user_id=%q user_tier=%q", rule.ID, rule.Level, rule.ServiceID, rule.ClientID, rule.OrgID, rule.UserID, rule.UserTier)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.rules = append(rr.rules, rule)
+	rr.version++
+	return nil
+}
+
+// UpdateRule replaces the rule with the same id, keeping its position
+// among the rules.
+func (rr *RuleRegistryImpl) UpdateRule(rule Rule) error {
+	log.Printf("action=update_rule id=%q level=%q service_id=%q client_id=%q org_id=%q user_id=%q user_tier=%q", rule.ID, rule.Level, rule.ServiceID, rule.ClientID, rule.OrgID, rule.UserID, rule.UserTier)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	i := slices.IndexFunc(rr.rules, func(r Rule) bool { return r.ID == rule.ID })
+	if i < 0 {
+		log.Printf("action=update_rule id=%q error=%q", rule.ID, ErrRuleNotFound)
+		return ErrRuleNotFound
+	}
+	rr.rules[i] = rule
+	rr.version++
+	return nil
+}
+
+func (rr *RuleRegistryImpl) RemoveRule(id string) error {
+	log.Printf("action=remove_rule id=%q", id)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	i := slices.IndexFunc(rr.rules, func(r Rule) bool { return r.ID == id })
+	if i < 0 {
+		log.Printf("action=remove_rule id=%q error=%q", id, ErrRuleNotFound)
+		return ErrRuleNotFound
+	}
+	rr.rules = slices.Delete(rr.rules, i, i+1)
+	rr.version++
+	return nil
+}
+
+// ReplaceRules swaps in new rules and a new default rule at once, and
+// returns the new version of the rule set.
+func (rr *RuleRegistryImpl) ReplaceRules(rules []Rule, defaultRule Rule) uint64 {
+	log.Printf("action=replace_rules rules=%d default_rule_id=%q", len(rules), defaultRule.ID)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.rules = slices.Clone(rules)
+	rr.defaultRule = defaultRule
+	rr.version++
+	return rr.version
+}
+
+// GetDefaultRule returns the rule used for requests that match no rule.
+func (rr *RuleRegistryImpl) GetDefaultRule() Rule {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.defaultRule
+}
+
+func (rr *RuleRegistryImpl) GetRule(id string) (Rule, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	i := slices.IndexFunc(rr.rules, func(r Rule) bool { return r.ID == id })
+	if i < 0 {
+		return Rule{}, ErrRuleNotFound
+	}
+	return rr.rules[i], nil
+}
+
+// GetRuleSet returns a copy of the registered rules and their version.
+func (rr *RuleRegistryImpl) GetRuleSet() RuleSet {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return RuleSet{
+		Version: rr.version,
+		Rules:   slices.Clone(rr.rules),
+	}
+}
+
+// RestoreRuleSet replaces the registered rules and their version with a
+// persisted rule set.
+func (rr *RuleRegistryImpl) RestoreRuleSet(ruleSet RuleSet) {
+	log.Printf("action=restore_rule_set version=%d rules=%d", ruleSet.Version, len(ruleSet.Rules))
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.rules = slices.Clone(ruleSet.Rules)
+	rr.version = ruleSet.Version
+}
+
+// MatchRule returns the most specific registered rule matching the request,
+// or the registry's default rule when no rule matches. See FindRule for how
+// rules are compared.
+func (rr *RuleRegistryImpl) MatchRule(req MatchRuleRequest) Rule {
+	rule, found := rr.FindRule(req)
+	if found {
+		return rule
+	}
+	rr.mu.RLock()
+	defaultRule := rr.defaultRule
+	rr.mu.RUnlock()
+	log.Printf("action=match_rule rule_id=%q level=%q service_id=%q client_id=%q org_id=%q user_id=%q user_tier=%q fallback=true", defaultRule.ID, req.Level, req.ServiceID, req.ClientID, req.OrgID, req.UserID, req.UserTier)
+	return defaultRule
+}
+
+// FindRule returns the most specific registered rule of the request's level
+// that matches the request. Specificity is compared on the user id, then the
+// user tier, the client id, the organization id and finally the service id;
+// rules that are equally specific are resolved in favour of the one added
+// first.
+func (rr *RuleRegistryImpl) FindRule(req MatchRuleRequest) (Rule, bool) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	var best *Rule
+	var bestSpecificity ruleSpecificity
+	for i := range rr.rules {
+		specificity, ok := matchRule(rr.rules[i], req)
+		if !ok {
+			continue
+		}
+		if best == nil || specificity.moreSpecificThan(bestSpecificity) {
+			best = &rr.rules[i]
+			bestSpecificity = specificity
+		}
+	}
+	if best == nil {
+		return Rule{}, false
+	}
+	log.Printf("action=match_rule rule_id=%q level=%q service_id=%q client_id=%q org_id=%q user_id=%q user_tier=%q", best.ID, req.Level, req.ServiceID, req.ClientID, req.OrgID, req.UserID, req.UserTier)
+	return *best, true
+}
+
+func (rr *RuleRegistryImpl) SetOrganization(orgID string, clientIDs []string) error {
+	log.Printf("action=set_organization id=%q client_ids=%q", orgID, clientIDs)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	for _, clientID := range clientIDs {
+		rr.clientOrgs[clientID] = orgID
+	}
+	return nil
+}
+
+// GetClientOrganization returns the id of the organization the client
+// belongs to, or an empty string if it belongs to none.
+func (rr *RuleRegistryImpl) GetClientOrganization(clientID string) string {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.clientOrgs[clientID]
+}
+
+func NewRuleRegistry(defaultRule Rule) RuleRegistry {
+	return &RuleRegistryImpl{
+		rules:       make([]Rule, 0),
+		defaultRule: defaultRule,
+		clientOrgs:  make(map[string]string),
+	}
+}
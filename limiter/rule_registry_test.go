@@ -0,0 +1,194 @@
+package limiter
+
+import "testing"
+
+func TestFindRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  []Rule
+		req    MatchRuleRequest
+		wantID string
+	}{
+		{
+			name:   "no rule matches",
+			rules:  []Rule{{ID: "other", ServiceID: "other"}},
+			req:    MatchRuleRequest{ServiceID: "svc", ClientID: "c1"},
+			wantID: "",
+		},
+		{
+			name: "exact service beats prefix and wildcard",
+			rules: []Rule{
+				{ID: "wildcard", ServiceID: "*"},
+				{ID: "prefix", ServiceID: "sv*"},
+				{ID: "exact", ServiceID: "svc"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc"},
+			wantID: "exact",
+		},
+		{
+			name: "prefix beats wildcard",
+			rules: []Rule{
+				{ID: "wildcard", ServiceID: "*"},
+				{ID: "prefix", ServiceID: "sv*"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc"},
+			wantID: "prefix",
+		},
+		{
+			name: "longer prefix wins",
+			rules: []Rule{
+				{ID: "short", ClientID: "mobile_*"},
+				{ID: "long", ClientID: "mobile_ios_*"},
+			},
+			req:    MatchRuleRequest{ClientID: "mobile_ios_17"},
+			wantID: "long",
+		},
+		{
+			name: "client is compared before service",
+			rules: []Rule{
+				{ID: "service", ServiceID: "svc"},
+				{ID: "client", ClientID: "c1"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc", ClientID: "c1"},
+			wantID: "client",
+		},
+		{
+			name: "client prefix beats exact service",
+			rules: []Rule{
+				{ID: "service", ServiceID: "svc", ClientID: "*"},
+				{ID: "client", ServiceID: "*", ClientID: "c*"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc", ClientID: "c1"},
+			wantID: "client",
+		},
+		{
+			name: "equally specific rules go to the first one",
+			rules: []Rule{
+				{ID: "first", ServiceID: "svc"},
+				{ID: "second", ServiceID: "svc"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc"},
+			wantID: "first",
+		},
+		{
+			name: "equally long prefixes go to the first one",
+			rules: []Rule{
+				{ID: "first", ClientID: "ab*"},
+				{ID: "second", ClientID: "a*", ServiceID: "*"},
+				{ID: "third", ClientID: "ab*"},
+			},
+			req:    MatchRuleRequest{ClientID: "abc"},
+			wantID: "first",
+		},
+		{
+			name: "user id is compared before everything else",
+			rules: []Rule{
+				{ID: "client", ServiceID: "svc", ClientID: "c1", UserTier: "gold"},
+				{ID: "user", UserID: "u1"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UserTier: "gold"},
+			wantID: "user",
+		},
+		{
+			name: "user tier is compared before client",
+			rules: []Rule{
+				{ID: "client", ClientID: "c1"},
+				{ID: "tier", UserTier: "gold"},
+			},
+			req:    MatchRuleRequest{ClientID: "c1", UserTier: "gold"},
+			wantID: "tier",
+		},
+		{
+			name: "rule for another tier does not match",
+			rules: []Rule{
+				{ID: "gold", UserTier: "gold"},
+				{ID: "any", ServiceID: "svc"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc", UserTier: "free"},
+			wantID: "any",
+		},
+		{
+			name: "user id prefix",
+			rules: []Rule{
+				{ID: "all", UserID: "*"},
+				{ID: "bots", UserID: "bot_*"},
+			},
+			req:    MatchRuleRequest{UserID: "bot_42"},
+			wantID: "bots",
+		},
+		{
+			name: "organization is compared after client",
+			rules: []Rule{
+				{ID: "org", Level: LevelClient, OrgID: "acme"},
+				{ID: "client", Level: LevelClient, ClientID: "c1"},
+			},
+			req:    MatchRuleRequest{Level: LevelClient, ClientID: "c1", OrgID: "acme"},
+			wantID: "client",
+		},
+		{
+			name: "organization is compared before service",
+			rules: []Rule{
+				{ID: "service", Level: LevelOrganization, ServiceID: "svc"},
+				{ID: "org", Level: LevelOrganization, OrgID: "acme"},
+			},
+			req:    MatchRuleRequest{Level: LevelOrganization, ServiceID: "svc", OrgID: "acme"},
+			wantID: "org",
+		},
+		{
+			name: "rule for another organization does not match",
+			rules: []Rule{
+				{ID: "other", Level: LevelOrganization, OrgID: "globex"},
+			},
+			req:    MatchRuleRequest{Level: LevelOrganization, OrgID: "acme"},
+			wantID: "",
+		},
+		{
+			name: "rules of another level do not match",
+			rules: []Rule{
+				{ID: "client", Level: LevelClient, ServiceID: "svc"},
+				{ID: "user", ServiceID: "*"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc"},
+			wantID: "user",
+		},
+		{
+			name: "empty level is user level",
+			rules: []Rule{
+				{ID: "user", Level: LevelUser, ServiceID: "svc"},
+			},
+			req:    MatchRuleRequest{ServiceID: "svc"},
+			wantID: "user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := NewRuleRegistry(DefaultRule)
+			for _, rule := range tt.rules {
+				err := rr.AddRule(rule)
+				if err != nil {
+					t.Fatalf("AddRule(%q) error = %v", rule.ID, err)
+				}
+			}
+			rule, found := rr.FindRule(tt.req)
+			if found != (tt.wantID != "") {
+				t.Fatalf("FindRule() found = %v, want %v", found, tt.wantID != "")
+			}
+			if rule.ID != tt.wantID {
+				t.Errorf("FindRule() rule = %q, want %q", rule.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestMatchRuleFallsBackToDefault(t *testing.T) {
+	defaultRule := Rule{ID: "fallback", RefillRatePerSecond: 1, MaxTokens: 10}
+	rr := NewRuleRegistry(defaultRule)
+	err := rr.AddRule(Rule{ID: "other", ServiceID: "other"})
+	if err != nil {
+		t.Fatalf("AddRule() error = %v", err)
+	}
+	rule := rr.MatchRule(MatchRuleRequest{ServiceID: "svc"})
+	if rule.ID != defaultRule.ID {
+		t.Errorf("MatchRule() rule = %q, want %q", rule.ID, defaultRule.ID)
+	}
+}
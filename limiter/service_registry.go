@@ -3,6 +3,9 @@ package limiter
 import (
 	"errors"
 	"log"
+	"slices"
+	"strings"
+	"sync"
 )
 
 var ErrServiceNotFound error = errors.New("service not found")
@@ -10,7 +13,7 @@ var ErrClientNotFound error = errors.New("client not found")
 
 type Service struct {
 	ID                 string
-	UsagePriceInTokens uint64
+	UsagePriceInTokens uint64 `json:"usage_price_in_tokens"`
 }
 
 type CreateServiceReqBody struct {
@@ -26,14 +29,20 @@ type ServiceRegistry interface {
 	CreateService(body CreateServiceReqBody) (Service, error)
 	UpdateService(id string, body UpdateServiceReqBody) (Service, error)
 	GetService(id string) (Service, error)
+	ListServices() []Service
+	DeleteService(id string) error
+	ReplaceServices(services []Service)
 }
 
 type ServiceRegistryImpl struct {
 	servicesMap map[string]*Service
+	mu          sync.RWMutex
 }
 
 func (sr *ServiceRegistryImpl) CreateService(body CreateServiceReqBody) (Service, error) {
 	log.Printf("action=create_service id=%q usage_price_in_tokens=%d", body.ID, body.UsagePriceInTokens)
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
 	sr.servicesMap[body.ID] = &Service{
 		ID:                 body.ID,
 		UsagePriceInTokens: body.UsagePriceInTokens,
@@ -44,20 +53,25 @@ func (sr *ServiceRegistryImpl) CreateService(body CreateServiceReqBody) (Service
 
 func (sr *ServiceRegistryImpl) UpdateService(id string, body UpdateServiceReqBody) (Service, error) {
 	log.Printf("action=update_service id=%q usage_price_in_tokens=%d", id, body.UsagePriceInTokens)
-	s, exists := sr.servicesMap[id]
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	_, exists := sr.servicesMap[id]
 	if !exists {
 		log.Printf("action=update_service id=%q error=%q", id, ErrServiceNotFound)
 		return Service{}, ErrServiceNotFound
 	}
-	s = &Service{
+	s := &Service{
 		ID:                 id,
 		UsagePriceInTokens: body.UsagePriceInTokens,
 	}
+	sr.servicesMap[id] = s
 	return *s, nil
 }
 
 func (sr *ServiceRegistryImpl) GetService(id string) (Service, error) {
 	log.Printf("action=get_service id=%q", id)
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
 	s, exists := sr.servicesMap[id]
 	if !exists {
 		log.Printf("action=get_service id=%q error=%q", id, ErrServiceNotFound)
@@ -66,6 +80,47 @@ func (sr *ServiceRegistryImpl) GetService(id string) (Service, error) {
 	return *s, nil
 }
 
+// ListServices returns every registered service ordered by id.
+func (sr *ServiceRegistryImpl) ListServices() []Service {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	services := make([]Service, 0, len(sr.servicesMap))
+	for _, s := range sr.servicesMap {
+		services = append(services, *s)
+	}
+	slices.SortFunc(services, func(a, b Service) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return services
+}
+
+// DeleteService removes a service. Requests for it fail from then on, but
+// the buckets already created for it are kept.
+func (sr *ServiceRegistryImpl) DeleteService(id string) error {
+	log.Printf("action=delete_service id=%q", id)
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	_, exists := sr.servicesMap[id]
+	if !exists {
+		log.Printf("action=delete_service id=%q error=%q", id, ErrServiceNotFound)
+		return ErrServiceNotFound
+	}
+	delete(sr.servicesMap, id)
+	return nil
+}
+
+// ReplaceServices swaps in a new set of services at once.
+func (sr *ServiceRegistryImpl) ReplaceServices(services []Service) {
+	log.Printf("action=replace_services services=%d", len(services))
+	servicesMap := make(map[string]*Service, len(services))
+	for _, s := range services {
+		servicesMap[s.ID] = &s
+	}
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.servicesMap = servicesMap
+}
+
 func NewServiceRegistry() ServiceRegistry {
 	return &ServiceRegistryImpl{
 		servicesMap: make(map[string]*Service),
@@ -0,0 +1,92 @@
+package limiter
+
+import (
+	"math"
+	"time"
+)
+
+// slidingWindowCounter approximates a sliding window log with two counters:
+// the tokens consumed in the current fixed window and in the one before it.
+// The previous window's count is weighted by how much of it still overlaps
+// the rolling window ending at now, assuming its requests were spread evenly.
+type slidingWindowCounter struct {
+	b *Bucket
+}
+
+func (c slidingWindowCounter) window() time.Duration {
+	return time.Duration(c.b.WindowSeconds) * time.Second
+}
+
+// counts returns the start of the fixed window containing now together with
+// the tokens consumed in it and in the window before it.
+func (c slidingWindowCounter) counts(now time.Time) (start time.Time, current, previous uint64) {
+	start = windowStart(now, c.window())
+	switch {
+	case start.Equal(c.b.WindowStart):
+		return start, c.b.WindowCount, c.b.PreviousWindowCount
+	case start.Equal(c.b.WindowStart.Add(c.window())):
+		return start, 0, c.b.WindowCount
+	}
+	return start, 0, 0
+}
+
+// waitForPrevious returns how far into a window the weighted previous count
+// has decayed enough to leave room for budget more tokens.
+func (c slidingWindowCounter) waitForPrevious(previous, budget uint64) time.Duration {
+	if previous <= budget {
+		return 0
+	}
+	remainingShare := float64(budget) / float64(previous)
+	return time.Duration(math.Ceil(float64(c.window()) * (1 - remainingShare)))
+}
+
+func (c slidingWindowCounter) Check(cost uint64, now time.Time) LimitStatus {
+	start, current, previous := c.counts(now)
+	elapsed := now.Sub(start)
+	weighted := float64(previous) * float64(c.window()-elapsed) / float64(c.window())
+	used := float64(current) + weighted
+	status := LimitStatus{
+		Remaining: uint64(max(float64(c.b.MaxTokens)-used, 0)),
+		Limit:     c.b.MaxTokens,
+	}
+	// The estimate only drops to zero once both the current window and the
+	// window after it have passed.
+	switch {
+	case current > 0:
+		status.ResetAfter = start.Add(2 * c.window()).Sub(now)
+	case previous > 0:
+		status.ResetAfter = start.Add(c.window()).Sub(now)
+	}
+	switch {
+	case used+float64(cost) <= float64(c.b.MaxTokens):
+		status.Allowed = true
+	case cost > c.b.MaxTokens:
+		status.RetryAfter = c.window()
+	case current+cost <= c.b.MaxTokens:
+		wait := c.waitForPrevious(previous, c.b.MaxTokens-current-cost)
+		status.RetryAfter = start.Add(wait).Sub(now)
+	default:
+		// Only once the current window has become the previous one can the
+		// request fit.
+		wait := c.waitForPrevious(current, c.b.MaxTokens-cost)
+		status.RetryAfter = start.Add(c.window() + wait).Sub(now)
+	}
+	return status
+}
+
+// Refund takes the amount off the current window first and off the previous
+// window with whatever is left.
+func (c slidingWindowCounter) Refund(amount uint64, now time.Time) {
+	start, current, previous := c.counts(now)
+	fromCurrent := min(amount, current)
+	c.b.WindowStart = start
+	c.b.WindowCount = current - fromCurrent
+	c.b.PreviousWindowCount = previous - min(amount-fromCurrent, previous)
+}
+
+func (c slidingWindowCounter) Consume(cost uint64, now time.Time) {
+	start, current, previous := c.counts(now)
+	c.b.WindowStart = start
+	c.b.WindowCount = current + cost
+	c.b.PreviousWindowCount = previous
+}
@@ -0,0 +1,68 @@
+package limiter
+
+import (
+	"sort"
+	"time"
+)
+
+// slidingWindowLog keeps the time of every consumed token for the last
+// WindowSeconds and admits a request only if the tokens in that window plus
+// the request's cost stay within MaxTokens.
+type slidingWindowLog struct {
+	b *Bucket
+}
+
+func (l slidingWindowLog) window() time.Duration {
+	return time.Duration(l.b.WindowSeconds) * time.Second
+}
+
+// live returns the entries of the log that are still inside the window
+// ending at now. The log is kept in ascending order.
+func (l slidingWindowLog) live(now time.Time) []time.Time {
+	windowStart := now.Add(-l.window())
+	i := sort.Search(len(l.b.Log), func(i int) bool {
+		return l.b.Log[i].After(windowStart)
+	})
+	return l.b.Log[i:]
+}
+
+func (l slidingWindowLog) Check(cost uint64, now time.Time) LimitStatus {
+	live := l.live(now)
+	used := uint64(len(live))
+	status := LimitStatus{
+		Remaining: l.b.MaxTokens - min(used, l.b.MaxTokens),
+		Limit:     l.b.MaxTokens,
+	}
+	if used > 0 {
+		status.ResetAfter = live[used-1].Add(l.window()).Sub(now)
+	}
+	switch {
+	case used+cost <= l.b.MaxTokens:
+		status.Allowed = true
+	case cost > l.b.MaxTokens:
+		status.RetryAfter = l.window()
+	default:
+		// The request fits once enough of the oldest entries have left the
+		// window.
+		expiring := live[used+cost-l.b.MaxTokens-1]
+		status.RetryAfter = expiring.Add(l.window()).Sub(now)
+	}
+	return status
+}
+
+// Refund drops the newest entries of the log, as those would otherwise stay
+// in the window the longest.
+func (l slidingWindowLog) Refund(amount uint64, now time.Time) {
+	live := l.live(now)
+	l.b.Log = live[:uint64(len(live))-min(amount, uint64(len(live)))]
+}
+
+func (l slidingWindowLog) Consume(cost uint64, now time.Time) {
+	live := l.live(now)
+	log := make([]time.Time, len(live), uint64(len(live))+cost)
+	copy(log, live)
+	for range cost {
+		log = append(log, now)
+	}
+	l.b.Log = log
+}
@@ -1,8 +1,10 @@
 package limiter
 
 import (
+	"context"
 	"errors"
 	"log"
+	"slices"
 	"sync"
 	"time"
 )
@@ -11,31 +13,91 @@ var ErrBucketNotFound = errors.New("bucket not found")
 var ErrCreateBucketIdCollision = errors.New("a bucket already exists with this id")
 
 type CreateBucketReqBody struct {
-	ID                  string
+	ID string
+	// Key groups the buckets of every limit enforced for the same service,
+	// client and user. It defaults to ID.
+	Key       string
+	Level     Level
+	LimitName string
+	// RuleID is the id of the rule, or pool, the bucket was created for.
+	RuleID string
+	// ServiceID and ClientID are the service and client of the request the
+	// bucket was created for, when the bucket is limited to them.
+	ServiceID string
+	ClientID  string
+	// UserTier is the tier of the request a user level bucket was created
+	// for.
+	UserTier            string
+	Algorithm           Algorithm
 	InitialTokens       uint64
 	RefillRatePerSecond uint64
 	MaxTokens           uint64
+	WindowSeconds       uint64
+	Period              Period
+	TimeZone            string
 }
 
 type Bucket struct {
-	ID                  string
-	Tokens              uint64     `json:"tokens"`
-	RefillRatePerSecond uint64     `json:"refill_rate_per_second"`
-	CreatedAt           time.Time  `json:"created_at"`
-	LastRefill          time.Time  `json:"last_refill"`
-	MaxTokens           uint64     `json:"max_tokens"`
-	Mu                  sync.Mutex `json:"-"`
+	ID        string
+	Key       string `json:"key,omitempty"`
+	Level     Level  `json:"level,omitempty"`
+	LimitName string `json:"limit_name,omitempty"`
+	RuleID    string `json:"rule_id,omitempty"`
+	ServiceID string `json:"service_id,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	// UserTier is the tier the rule of a user level bucket was matched for.
+	UserTier            string      `json:"user_tier,omitempty"`
+	Algorithm           Algorithm   `json:"algorithm,omitempty"`
+	Tokens              uint64      `json:"tokens"`
+	RefillRatePerSecond uint64      `json:"refill_rate_per_second"`
+	CreatedAt           time.Time   `json:"created_at"`
+	LastRefill          time.Time   `json:"last_refill"`
+	MaxTokens           uint64      `json:"max_tokens"`
+	WindowSeconds       uint64      `json:"window_seconds,omitempty"`
+	Period              Period      `json:"period,omitempty"`
+	TimeZone            string      `json:"time_zone,omitempty"`
+	Log                 []time.Time `json:"log,omitempty"`
+	WindowStart         time.Time   `json:"window_start,omitzero"`
+	WindowCount         uint64      `json:"window_count,omitempty"`
+	PreviousWindowCount uint64      `json:"previous_window_count,omitempty"`
+	TAT                 time.Time   `json:"tat,omitzero"`
+	QueueEnd            time.Time   `json:"queue_end,omitzero"`
+	Mu                  sync.Mutex  `json:"-"`
 }
 
 type AccessStatusResponse struct {
 	IsAllowed         bool
 	RetryAfterSeconds uint64
+	// RetryAfterMilliseconds is RetryAfterSeconds with millisecond
+	// precision.
+	RetryAfterMilliseconds uint64
+	// DelayMilliseconds is how long the caller must wait before doing the
+	// admitted work. Only leaky buckets delay admitted requests.
+	DelayMilliseconds uint64
+	// DeniedLimit and DeniedLevel name the limit with the longest retry
+	// after among the ones that denied the request, and the level of the
+	// hierarchy it belongs to.
+	DeniedLimit string
+	DeniedLevel Level
+	// Remaining and Limit describe the bucket with the fewest tokens left
+	// after the request, and ResetAfterSeconds is how long it takes until
+	// every bucket of the request is full again.
+	Remaining         uint64
+	Limit             uint64
+	ResetAfterSeconds uint64
+	// RuleID is the rule of the bucket that denied the request, or of the
+	// bucket Remaining describes if it was allowed.
+	RuleID string
+	// ExceedsLimit is set on denied requests that cost more than one of
+	// their buckets holds when it is full, so they will never be allowed.
+	ExceedsLimit bool
 }
 
 type ConsumeServiceRequest struct {
 	ServiceID   string
 	ClientID    string
 	UserID      string
+	UserTier    string
 	UsageAmount uint64
 }
 
@@ -43,16 +105,53 @@ type BucketStorage interface {
 	CreateBucket(body CreateBucketReqBody) error
 	RestoreBucket(body *Bucket) error
 	ConsumeService(body ConsumeServiceRequest) (AccessStatusResponse, error)
+	CheckService(body ConsumeServiceRequest) (AccessStatusResponse, error)
+	ConsumeServices(bodies []ConsumeServiceRequest) (AccessStatusResponse, []AccessStatusResponse, error)
+	WaitService(ctx context.Context, body ConsumeServiceRequest) (AccessStatusResponse, error)
+	RefundService(body RefundServiceRequest) (RefundResponse, error)
+	Reserve(body ReserveRequest) (ReserveResponse, error)
+	Commit(body CommitRequest) (CommitResponse, error)
+	Cancel(reservationID string) error
+	ReleaseExpiredReservations(now time.Time) int
+	RestoreReservation(reservation *Reservation) error
+	GetAllReservations() []Reservation
 	GetAllBuckets() []*Bucket
 	GetBucket(ID string) (*Bucket, error)
+	ListBuckets(body ListBucketsRequest) ([]*Bucket, string)
+	ResetBucket(ID string) error
+	SetBucketTokens(ID string, tokens uint64) error
+	DeleteBucket(ID string) error
+	ForgetUnmatchedScopes()
+	MigrateRuleBuckets(ruleID string, limits []Limit) (int, int)
 }
 
 type BucketStorageImpl struct {
 	BucketsMap      map[string]*Bucket
 	ServiceRegistry ServiceRegistry
+	RuleRegistry    RuleRegistry
+	PoolRegistry    PoolRegistry
+	// bucketsByKey indexes BucketsMap by Bucket.Key.
+	bucketsByKey map[string][]*Bucket
+	mu           sync.RWMutex
+	// refunds maps the idempotency keys of applied refunds to when they were
+	// applied, and refundKeys lists them in that order so expired keys can
+	// be dropped from the front.
+	refunds    map[string]time.Time
+	refundKeys []claimedRefundKey
+	refundsMu  sync.Mutex
+	// reservations holds the reservations that are neither committed nor
+	// cancelled yet, by id.
+	reservations   map[string]*Reservation
+	reservationsMu sync.Mutex
+	// waiters holds the queue of WaitService calls waiting on each bucket,
+	// by bucket id.
+	waiters   map[string][]*waiter
+	waitersMu sync.Mutex
 }
 
 func (bs *BucketStorageImpl) GetBucket(id string) (*Bucket, error) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
 	b, exists := bs.BucketsMap[id]
 	if !exists {
 		return nil, ErrBucketNotFound
@@ -63,81 +162,371 @@ func (bs *BucketStorageImpl) GetBucket(id string) (*Bucket, error) {
 func (bs *BucketStorageImpl) RestoreBucket(bucket *Bucket) error {
 	bucket.Mu.Lock()
 	defer bucket.Mu.Unlock()
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
 
 	_, exists := bs.BucketsMap[bucket.ID]
 	if exists {
 		log.Printf("level=warn event=restore_bucket bucket already exists")
 		return ErrCreateBucketIdCollision
 	}
-	bs.BucketsMap[bucket.ID] = bucket
+	if bucket.Key == "" {
+		bucket.Key = bucket.ID
+	}
+	bucket.Level = levelOf(bucket.Level)
+	bs.addBucket(bucket)
 	return nil
 }
 
 func (bs *BucketStorageImpl) CreateBucket(body CreateBucketReqBody) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return bs.createBucket(body)
+}
+
+// createBucket expects bs.mu to be held for writing.
+func (bs *BucketStorageImpl) createBucket(body CreateBucketReqBody) error {
 	if body.MaxTokens <= 0 {
-		log.Fatalf("Max Tokens is not defined for bucket, bucket_id:%s", body.ID)
+		log.Printf("event=create_bucket status=error bucket_id=%q errors=%q", body.ID, ErrInvalidMaxTokens)
+		return ErrInvalidMaxTokens
+	}
+	log.Printf("event=create_bucket bucket_id=%q rule_id=%q limit_name=%q algorithm=%q initial_tokens=%d refill_rate_per_second=%d max_tokens=%d window_seconds=%d period=%q time_zone=%q", body.ID, body.RuleID, body.LimitName, body.Algorithm, body.InitialTokens, body.RefillRatePerSecond, body.MaxTokens, body.WindowSeconds, body.Period, body.TimeZone)
+	err := validateAlgorithm(body)
+	if err != nil {
+		log.Printf("event=create_bucket status=error bucket_id=%q errors=%q", body.ID, err)
+		return err
 	}
-	log.Printf("event=create_bucket bucket_id=%q initial_tokens=%d refill_rate_per_second=%d max_tokens=%d", body.ID, body.InitialTokens, body.RefillRatePerSecond, body.MaxTokens)
 	_, bExists := bs.BucketsMap[body.ID]
 	if bExists {
 		log.Printf("event=create_bucket status=error errors=%q", ErrCreateBucketIdCollision)
 		return ErrCreateBucketIdCollision
 	}
+	key := body.Key
+	if key == "" {
+		key = body.ID
+	}
 	newBucket := &Bucket{
 		ID:                  body.ID,
+		Key:                 key,
+		Level:               levelOf(body.Level),
+		LimitName:           body.LimitName,
+		RuleID:              body.RuleID,
+		ServiceID:           body.ServiceID,
+		ClientID:            body.ClientID,
+		UserTier:            body.UserTier,
+		Algorithm:           body.Algorithm,
 		Tokens:              body.InitialTokens,
 		RefillRatePerSecond: body.RefillRatePerSecond,
 		MaxTokens:           body.MaxTokens,
+		WindowSeconds:       body.WindowSeconds,
+		Period:              body.Period,
+		TimeZone:            body.TimeZone,
 		Mu:                  sync.Mutex{},
 		CreatedAt:           time.Now(),
 		LastRefill:          time.Now(),
 	}
-	bs.BucketsMap[newBucket.ID] = newBucket
+	bs.addBucket(newBucket)
 	log.Printf("event=bucket_created bucket_id=%q", newBucket.ID)
 
 	return nil
 }
 
-func (bs *BucketStorageImpl) ConsumeService(body ConsumeServiceRequest) (accRes AccessStatusResponse, err error) {
-	log.Printf("event=consume_service status=started client_id=%s user_id=%s", body.ClientID, body.UserID)
+func (bs *BucketStorageImpl) addBucket(b *Bucket) {
+	bs.BucketsMap[b.ID] = b
+	bs.bucketsByKey[b.Key] = append(bs.bucketsByKey[b.Key], b)
+}
+
+// bucketScope is one level of the hierarchy a request is limited at: the
+// key its buckets are grouped under and what to match rules against. Pool
+// scopes set the pool's id and limits instead of matching a rule.
+// rematchTier lets a user level scope move its buckets to the rule of the
+// request's tier.
+type bucketScope struct {
+	key         string
+	match       MatchRuleRequest
+	poolID      string
+	limits      []Limit
+	rematchTier bool
+}
+
+// getOrCreateBuckets returns the buckets of every limit enforced for the
+// request at the user, client and organization levels and in the pools of
+// the service, creating them from the matching rules on first use. Only
+// requests that consume tokens pass rematchTier, so that checks and refunds
+// never move a user's buckets to another rule.
+func (bs *BucketStorageImpl) getOrCreateBuckets(body ConsumeServiceRequest, rematchTier bool) ([]*Bucket, error) {
+	orgID := bs.RuleRegistry.GetClientOrganization(body.ClientID)
+	scopes := []bucketScope{
+		{
+			key: GetBucketID(GetBucketIDRequest{
+				ClientID:  body.ClientID,
+				ServiceID: body.ServiceID,
+				UserID:    body.UserID,
+			}),
+			match: MatchRuleRequest{
+				Level:     LevelUser,
+				ServiceID: body.ServiceID,
+				ClientID:  body.ClientID,
+				OrgID:     orgID,
+				UserID:    body.UserID,
+				UserTier:  body.UserTier,
+			},
+			rematchTier: rematchTier,
+		},
+		{
+			key: GetClientBucketID(body.ServiceID, body.ClientID),
+			match: MatchRuleRequest{
+				Level:     LevelClient,
+				ServiceID: body.ServiceID,
+				ClientID:  body.ClientID,
+				OrgID:     orgID,
+			},
+		},
+	}
+	if orgID != "" {
+		scopes = append(scopes, bucketScope{
+			key: GetOrgBucketID(body.ServiceID, orgID),
+			match: MatchRuleRequest{
+				Level:     LevelOrganization,
+				ServiceID: body.ServiceID,
+				OrgID:     orgID,
+			},
+		})
+	}
+	for _, pool := range bs.PoolRegistry.GetServicePools(body.ServiceID) {
+		scopes = append(scopes, bucketScope{
+			key:    GetPoolBucketID(pool.ID, body.ClientID),
+			match:  MatchRuleRequest{Level: LevelPool, ClientID: body.ClientID},
+			poolID: pool.ID,
+			limits: pool.Limits,
+		})
+	}
+
+	buckets := make([]*Bucket, 0, len(scopes))
+	for _, scope := range scopes {
+		scopeBuckets, err := bs.getOrCreateScopeBuckets(scope)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, scopeBuckets...)
+	}
+	return buckets, nil
+}
+
+// getOrCreateScopeBuckets returns the buckets of one scope. Pool scopes use
+// the pool's limits and user level scopes fall back to the default rule;
+// client and organization scopes without a matching rule are not limited and
+// remembered as having no buckets. The buckets of a user whose tier changed
+// are moved to the rule matching the new tier.
+func (bs *BucketStorageImpl) getOrCreateScopeBuckets(scope bucketScope) ([]*Bucket, error) {
+	bs.mu.RLock()
+	buckets, known := bs.bucketsByKey[scope.key]
+	tierChanged := known && userTierChanged(scope, buckets)
+	bs.mu.RUnlock()
+	if known && !tierChanged {
+		return buckets, nil
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if buckets, known := bs.bucketsByKey[scope.key]; known {
+		if userTierChanged(scope, buckets) {
+			return bs.rematchUserBuckets(scope, buckets)
+		}
+		return buckets, nil
+	}
+	var rule Rule
+	switch {
+	case scope.limits != nil:
+		rule = Rule{ID: scope.poolID, Limits: scope.limits}
+	case levelOf(scope.match.Level) == LevelUser:
+		rule = bs.RuleRegistry.MatchRule(scope.match)
+	default:
+		var found bool
+		rule, found = bs.RuleRegistry.FindRule(scope.match)
+		if !found {
+			bs.bucketsByKey[scope.key] = []*Bucket{}
+			return nil, nil
+		}
+	}
+	err := bs.addRuleBuckets(scope, rule)
+	if err != nil {
+		return nil, err
+	}
+	return bs.bucketsByKey[scope.key], nil
+}
+
+// userTierChanged reports whether the buckets of a user level scope were
+// created for another tier than the one of the request. A request without a
+// tier keeps the current rule. bs.mu must be held.
+func userTierChanged(scope bucketScope, buckets []*Bucket) bool {
+	if !scope.rematchTier || scope.match.UserTier == "" || len(buckets) == 0 {
+		return false
+	}
+	return buckets[0].UserTier != scope.match.UserTier
+}
+
+// rematchUserBuckets moves the buckets of a user level scope to the rule
+// matching the request's tier, the way MigrateRuleBuckets moves the buckets
+// of a changed rule: a bucket whose limit kept its algorithm keeps what was
+// consumed from it, and the others are replaced by new buckets. bs.mu must
+// be held for writing.
+func (bs *BucketStorageImpl) rematchUserBuckets(scope bucketScope, buckets []*Bucket) ([]*Bucket, error) {
+	rule := bs.RuleRegistry.MatchRule(scope.match)
+	log.Printf("event=user_tier_changed key=%q user_tier=%q old_user_tier=%q rule_id=%q old_rule_id=%q", scope.key, scope.match.UserTier, buckets[0].UserTier, rule.ID, buckets[0].RuleID)
+	limits := rule.BucketLimits()
+	now := time.Now()
+	kept := make([]*Bucket, 0, len(buckets))
+	for _, b := range buckets {
+		b.Mu.Lock()
+		i := slices.IndexFunc(limits, func(l Limit) bool { return l.Name == b.LimitName })
+		switch {
+		case b.RuleID == rule.ID:
+			b.UserTier = scope.match.UserTier
+			kept = append(kept, b)
+		case i >= 0 && algorithmOf(limits[i].Algorithm) == algorithmOf(b.Algorithm):
+			migrateBucket(b, limits[i], now)
+			b.RuleID = rule.ID
+			b.UserTier = scope.match.UserTier
+			kept = append(kept, b)
+		default:
+			delete(bs.BucketsMap, b.ID)
+		}
+		b.Mu.Unlock()
+	}
+	bs.bucketsByKey[scope.key] = kept
+	err := bs.addRuleBuckets(scope, rule)
+	if err != nil {
+		return nil, err
+	}
+	return bs.bucketsByKey[scope.key], nil
+}
+
+// addRuleBuckets adds the buckets of the rule's limits that the scope does
+// not have yet. bs.mu must be held for writing.
+func (bs *BucketStorageImpl) addRuleBuckets(scope bucketScope, rule Rule) error {
+	for _, limit := range rule.BucketLimits() {
+		id := GetLimitBucketID(scope.key, limit.Name)
+		if slices.ContainsFunc(bs.bucketsByKey[scope.key], func(b *Bucket) bool { return b.ID == id }) {
+			continue
+		}
+		// The other limits of a key are kept when one of its buckets is
+		// deleted.
+		if b, exists := bs.BucketsMap[id]; exists {
+			bs.bucketsByKey[scope.key] = append(bs.bucketsByKey[scope.key], b)
+			continue
+		}
+		err := bs.createBucket(CreateBucketReqBody{
+			ID:                  id,
+			Key:                 scope.key,
+			Level:               scope.match.Level,
+			LimitName:           limit.Name,
+			RuleID:              rule.ID,
+			ServiceID:           scope.match.ServiceID,
+			ClientID:            scope.match.ClientID,
+			UserTier:            scope.match.UserTier,
+			Algorithm:           limit.Algorithm,
+			InitialTokens:       limit.InitialTokens,
+			RefillRatePerSecond: limit.RefillRatePerSecond,
+			MaxTokens:           limit.MaxTokens,
+			WindowSeconds:       limit.WindowSeconds,
+			Period:              limit.Period,
+			TimeZone:            limit.TimeZone,
+		})
+		if err != nil {
+			log.Printf("event=create_rule_buckets status=error key=%q rule_id=%q limit_name=%q err=%q", scope.key, rule.ID, limit.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// chargesFor returns what the request costs in each of the buckets it is
+// limited by. rematchTier is passed on to getOrCreateBuckets.
+func (bs *BucketStorageImpl) chargesFor(body ConsumeServiceRequest, rematchTier bool) ([]charge, error) {
 	requestedService, err := bs.ServiceRegistry.GetService(body.ServiceID)
 	if err != nil {
 		log.Printf("error=service_not_found client_id=%q service_id=%q err=%v", body.ClientID, body.ServiceID, err)
-		return
+		return nil, err
 	}
 
-	bucketID := GetBucketID(GetBucketIDRequest{
-		ClientID:  body.ClientID,
-		ServiceID: body.ServiceID,
-		UserID:    body.UserID,
-	})
-	b := bs.BucketsMap[bucketID]
+	buckets, err := bs.getOrCreateBuckets(body, rematchTier)
+	if err != nil {
+		return nil, err
+	}
 
-	if b == nil {
-		return accRes, ErrBucketNotFound
+	consumeAmount := requestedService.UsagePriceInTokens * body.UsageAmount
+	log.Printf("event=get_bucket_status service_id=%s client_id=%s user_id=%s buckets=%d usage_price=%d", body.ServiceID, body.ClientID, body.UserID, len(buckets), requestedService.UsagePriceInTokens)
+	charges := make([]charge, 0, len(buckets))
+	for _, b := range buckets {
+		charges = append(charges, charge{bucket: b, cost: consumeAmount})
 	}
+	return charges, nil
+}
 
-	refill(b)
+func (bs *BucketStorageImpl) ConsumeService(body ConsumeServiceRequest) (accRes AccessStatusResponse, err error) {
+	log.Printf("event=consume_service status=started client_id=%s user_id=%s", body.ClientID, body.UserID)
+	charges, err := bs.chargesFor(body, true)
+	if err != nil {
+		return
+	}
+	accRes, err = consumeAll(charges)
+	if err != nil {
+		return
+	}
+	if !accRes.IsAllowed {
+		log.Printf("event=insufficient_tokens service_id=%s client_id=%s user_id=%s denied_level=%q denied_limit=%q retry_after=%d", body.ServiceID, body.ClientID, body.UserID, accRes.DeniedLevel, accRes.DeniedLimit, accRes.RetryAfterSeconds)
+		return
+	}
+	log.Printf("event=consume_tokens client_id=%s service_id=%s user_id=%s usage_amount=%d remaining=%d delay_ms=%d", body.ClientID, body.ServiceID, body.UserID, body.UsageAmount, accRes.Remaining, accRes.DelayMilliseconds)
+	return
+}
 
-	b.Mu.Lock()
-	defer b.Mu.Unlock()
+// ConsumeServices consumes the tokens of every request or of none of them.
+// The first response describes the batch as a whole: it is only allowed when
+// all requests fit, counting requests charged to the same bucket together.
+// The others tell for each request whether it fits on its own.
+func (bs *BucketStorageImpl) ConsumeServices(bodies []ConsumeServiceRequest) (accRes AccessStatusResponse, itemRes []AccessStatusResponse, err error) {
+	log.Printf("event=consume_services status=started requests=%d", len(bodies))
+	items := make([][]charge, 0, len(bodies))
+	for _, body := range bodies {
+		bodyCharges, err := bs.chargesFor(body, true)
+		if err != nil {
+			return accRes, nil, err
+		}
+		items = append(items, bodyCharges)
+	}
+	accRes, itemRes, err = consumeBatch(items)
+	if err != nil {
+		return
+	}
+	if !accRes.IsAllowed {
+		log.Printf("event=insufficient_tokens requests=%d denied_level=%q denied_limit=%q retry_after=%d", len(bodies), accRes.DeniedLevel, accRes.DeniedLimit, accRes.RetryAfterSeconds)
+		return
+	}
+	log.Printf("event=consume_tokens requests=%d remaining=%d delay_ms=%d", len(bodies), accRes.Remaining, accRes.DelayMilliseconds)
+	return
+}
 
-	log.Printf("event=get_bucket_status service_id=%s client_id=%s user_id=%s tokens=%d usage_price=%d", body.ServiceID, body.ClientID, body.UserID, b.Tokens, requestedService.UsagePriceInTokens)
-	consumeAmount := requestedService.UsagePriceInTokens * body.UsageAmount
-	if b.Tokens < consumeAmount {
-		accRes.IsAllowed = false
-		accRes.RetryAfterSeconds = (consumeAmount - b.Tokens) / b.RefillRatePerSecond
-		log.Printf("event=insufficient_tokens service_id=%s client_id=%s user_id=%s tokens=%d retry_after=%d", body.ServiceID, body.ClientID, body.UserID, b.Tokens, accRes.RetryAfterSeconds)
+// CheckService answers like ConsumeService would, but does not consume any
+// tokens.
+func (bs *BucketStorageImpl) CheckService(body ConsumeServiceRequest) (accRes AccessStatusResponse, err error) {
+	log.Printf("event=check_service status=started client_id=%s user_id=%s", body.ClientID, body.UserID)
+	charges, err := bs.chargesFor(body, false)
+	if err != nil {
 		return
 	}
-	b.Tokens -= consumeAmount
-	accRes.IsAllowed = true
-	accRes.RetryAfterSeconds = 0
-	log.Printf("event=consume_tokens client_id=%s service_id=%s user_id=%s tokens_consumed=%d tokens_left=%d", body.ClientID, body.ServiceID, body.UserID, consumeAmount, b.Tokens)
+	accRes, err = checkAll(charges)
+	if err != nil {
+		return
+	}
+	log.Printf("event=check_service status=success client_id=%s service_id=%s user_id=%s allowed=%t remaining=%d limit=%d reset_after=%d", body.ClientID, body.ServiceID, body.UserID, accRes.IsAllowed, accRes.Remaining, accRes.Limit, accRes.ResetAfterSeconds)
 	return
 }
 
 func (bs *BucketStorageImpl) GetAllBuckets() []*Bucket {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
 	buckets := make([]*Bucket, 0)
 	for _, b := range bs.BucketsMap {
 		buckets = append(buckets, b)
@@ -145,27 +534,92 @@ func (bs *BucketStorageImpl) GetAllBuckets() []*Bucket {
 	return buckets
 }
 
-func refill(b *Bucket) {
+// tokenBucket adds RefillRatePerSecond tokens for every whole second since
+// LastRefill, never holding more than MaxTokens.
+type tokenBucket struct {
+	b *Bucket
+}
+
+func (t tokenBucket) refilledTokens(now time.Time) uint64 {
+	refilled := uint64(now.Sub(t.b.LastRefill).Seconds()) * t.b.RefillRatePerSecond
+	return min(t.b.Tokens+refilled, t.b.MaxTokens)
+}
+
+// resetAfter returns how long it takes to refill from tokens to MaxTokens.
+func (t tokenBucket) resetAfter(tokens uint64, now time.Time) time.Duration {
+	if tokens >= t.b.MaxTokens {
+		return 0
+	}
+	return t.refillAfter(t.b.MaxTokens-tokens, now)
+}
+
+// refillAfter returns how long it takes until missing more tokens have been
+// added. Tokens are only added on whole seconds since LastRefill.
+func (t tokenBucket) refillAfter(missing uint64, now time.Time) time.Duration {
+	if missing == 0 || t.b.RefillRatePerSecond == 0 {
+		return 0
+	}
+	seconds := (missing + t.b.RefillRatePerSecond - 1) / t.b.RefillRatePerSecond
+	elapsed := now.Sub(t.b.LastRefill).Truncate(time.Second)
+	return t.b.LastRefill.Add(elapsed + time.Duration(seconds)*time.Second).Sub(now)
+}
+
+func (t tokenBucket) Check(cost uint64, now time.Time) LimitStatus {
+	tokens := t.refilledTokens(now)
+	status := LimitStatus{
+		Remaining:  tokens,
+		Limit:      t.b.MaxTokens,
+		ResetAfter: t.resetAfter(tokens, now),
+	}
+	if tokens >= cost {
+		status.Allowed = true
+		return status
+	}
+	status.RetryAfter = t.refillAfter(cost-tokens, now)
+	return status
+}
+
+func (t tokenBucket) Consume(cost uint64, now time.Time) {
+	refill(t.b, now)
+	t.b.Tokens -= cost
+}
+
+func (t tokenBucket) Refund(amount uint64, now time.Time) {
+	refill(t.b, now)
+	t.b.Tokens = min(t.b.Tokens+amount, t.b.MaxTokens)
+}
+
+func refill(b *Bucket, now time.Time) {
 	if b == nil {
 		return
 	}
-	b.Mu.Lock()
-	defer b.Mu.Unlock()
-	refilled := uint64(time.Since(b.LastRefill).Seconds()) * b.RefillRatePerSecond
-	if refilled > 0 {
-		b.Tokens += refilled
-		if b.Tokens >= b.MaxTokens {
-			b.Tokens = b.MaxTokens
-		}
-		log.Printf("event=bucket_refilled bucket_id=%s tokens_added=%d new_tokens=%d", b.ID, refilled, b.Tokens)
+	tokens := tokenBucket{b}.refilledTokens(now)
+	if tokens > b.Tokens {
+		log.Printf("event=bucket_refilled bucket_id=%s tokens_added=%d new_tokens=%d", b.ID, tokens-b.Tokens, tokens)
+		b.Tokens = tokens
+	}
+	if tokens >= b.MaxTokens {
+		b.LastRefill = now
+		return
+	}
+	// Only move LastRefill by the whole seconds that were refilled, so the
+	// fraction of a second that has passed towards the next refill is kept
+	elapsed := now.Sub(b.LastRefill).Truncate(time.Second)
+	if elapsed > 0 {
+		b.LastRefill = b.LastRefill.Add(elapsed)
 	}
-	b.LastRefill = time.Now()
 }
 
-func NewBucketStorage(serviceRegistry ServiceRegistry) BucketStorage {
+func NewBucketStorage(serviceRegistry ServiceRegistry, ruleRegistry RuleRegistry, poolRegistry PoolRegistry) BucketStorage {
 	return &BucketStorageImpl{
 		BucketsMap:      make(map[string]*Bucket),
 		ServiceRegistry: serviceRegistry,
+		RuleRegistry:    ruleRegistry,
+		PoolRegistry:    poolRegistry,
+		bucketsByKey:    make(map[string][]*Bucket),
+		refunds:         make(map[string]time.Time),
+		reservations:    make(map[string]*Reservation),
+		waiters:         make(map[string][]*waiter),
 	}
 }
 
@@ -178,3 +632,30 @@ type GetBucketIDRequest struct {
 func GetBucketID(dto GetBucketIDRequest) string {
 	return dto.ServiceID + "_" + dto.ClientID + "_" + dto.UserID
 }
+
+// GetClientBucketID returns the key of the buckets shared by all users of a
+// client for a service.
+func GetClientBucketID(serviceID string, clientID string) string {
+	return "client:" + serviceID + "_" + clientID
+}
+
+// GetOrgBucketID returns the key of the buckets shared by all clients of an
+// organization for a service.
+func GetOrgBucketID(serviceID string, orgID string) string {
+	return "org:" + serviceID + "_" + orgID
+}
+
+// GetPoolBucketID returns the key of the buckets a client's requests to
+// every service of a pool draw from.
+func GetPoolBucketID(poolID string, clientID string) string {
+	return "pool:" + poolID + "_" + clientID
+}
+
+// GetLimitBucketID returns the id of the bucket enforcing the named limit
+// for key. The bucket of an unnamed limit uses key itself.
+func GetLimitBucketID(key string, limitName string) string {
+	if limitName == "" {
+		return key
+	}
+	return key + "#" + limitName
+}
@@ -0,0 +1,116 @@
+package limiter
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTierTestStorage(t *testing.T) BucketStorage {
+	t.Helper()
+	sr := NewServiceRegistry()
+	_, err := sr.CreateService(CreateServiceReqBody{ID: "svc", UsagePriceInTokens: 1})
+	if err != nil {
+		t.Fatalf("CreateService() error = %v", err)
+	}
+	rr := NewRuleRegistry(DefaultRule)
+	rules := []Rule{
+		{ID: "free", ServiceID: "svc", RefillRatePerSecond: 1, InitialTokens: 10, MaxTokens: 10},
+		{ID: "gold", ServiceID: "svc", UserTier: "gold", RefillRatePerSecond: 1, InitialTokens: 1000, MaxTokens: 1000},
+	}
+	for _, rule := range rules {
+		err := rr.AddRule(rule)
+		if err != nil {
+			t.Fatalf("AddRule(%q) error = %v", rule.ID, err)
+		}
+	}
+	return NewBucketStorage(sr, rr, NewPoolRegistry())
+}
+
+func TestUserTierIsKeptWithoutTier(t *testing.T) {
+	gold := ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UserTier: "gold", UsageAmount: 1}
+	noTier := ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UsageAmount: 1}
+	tests := []struct {
+		name string
+		call func(bs BucketStorage) error
+	}{
+		{name: "check", call: func(bs BucketStorage) error {
+			_, err := bs.CheckService(noTier)
+			return err
+		}},
+		{name: "consume", call: func(bs BucketStorage) error {
+			_, err := bs.ConsumeService(noTier)
+			return err
+		}},
+		{name: "refund", call: func(bs BucketStorage) error {
+			_, err := bs.RefundService(RefundServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UsageAmount: 1})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := newTierTestStorage(t)
+			_, err := bs.ConsumeService(gold)
+			if err != nil {
+				t.Fatalf("ConsumeService() error = %v", err)
+			}
+			err = tt.call(bs)
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			res, err := bs.CheckService(gold)
+			if err != nil {
+				t.Fatalf("CheckService() error = %v", err)
+			}
+			if res.Limit != 1000 || res.Remaining < 998 {
+				t.Errorf("gold user remaining = %d/%d, want at least 998/1000", res.Remaining, res.Limit)
+			}
+		})
+	}
+}
+
+func TestUserTierChangeMovesBuckets(t *testing.T) {
+	bs := newTierTestStorage(t)
+	_, err := bs.ConsumeService(ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UserTier: "gold", UsageAmount: 1})
+	if err != nil {
+		t.Fatalf("ConsumeService() error = %v", err)
+	}
+	// A check with another tier does not move the buckets
+	res, err := bs.CheckService(ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UserTier: "free"})
+	if err != nil {
+		t.Fatalf("CheckService() error = %v", err)
+	}
+	if res.Limit != 1000 {
+		t.Errorf("limit after check = %d, want 1000", res.Limit)
+	}
+	res, err = bs.ConsumeService(ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UserTier: "free", UsageAmount: 1})
+	if err != nil {
+		t.Fatalf("ConsumeService() error = %v", err)
+	}
+	if res.Limit != 10 {
+		t.Errorf("limit after tier change = %d, want 10", res.Limit)
+	}
+}
+
+func TestCreateBucketWithoutMaxTokens(t *testing.T) {
+	bs := NewBucketStorage(NewServiceRegistry(), NewRuleRegistry(DefaultRule), NewPoolRegistry())
+	err := bs.CreateBucket(CreateBucketReqBody{ID: "b", RefillRatePerSecond: 1})
+	if !errors.Is(err, ErrInvalidMaxTokens) {
+		t.Errorf("CreateBucket() error = %v, want %v", err, ErrInvalidMaxTokens)
+	}
+}
+
+func TestRuleWithoutMaxTokensFailsRequests(t *testing.T) {
+	sr := NewServiceRegistry()
+	_, err := sr.CreateService(CreateServiceReqBody{ID: "svc", UsagePriceInTokens: 1})
+	if err != nil {
+		t.Fatalf("CreateService() error = %v", err)
+	}
+	rr := NewRuleRegistry(DefaultRule)
+	// RestoreRuleSet does not validate the rules it is given
+	rr.RestoreRuleSet(RuleSet{Version: 1, Rules: []Rule{{ID: "broken", ServiceID: "svc", RefillRatePerSecond: 1}}})
+	bs := NewBucketStorage(sr, rr, NewPoolRegistry())
+	_, err = bs.ConsumeService(ConsumeServiceRequest{ServiceID: "svc", ClientID: "c1", UserID: "u1", UsageAmount: 1})
+	if !errors.Is(err, ErrInvalidMaxTokens) {
+		t.Errorf("ConsumeService() error = %v, want %v", err, ErrInvalidMaxTokens)
+	}
+}
@@ -0,0 +1,122 @@
+package limiter
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+// waiter is a request waiting for capacity in WaitService.
+type waiter struct {
+	// ready is signalled whenever the waiter may have moved to the head of
+	// one of its queues.
+	ready chan struct{}
+}
+
+// WaitService consumes the tokens of the request like ConsumeService, but
+// when they are not available it waits until they are instead of denying
+// the request. It only denies the request when the tokens would not be
+// available before the context's deadline or it costs more than one of its
+// buckets can hold, and returns the context's error when it is cancelled
+// first.
+//
+// Waiters are served in the order they arrived: every waiter joins the queue
+// of each bucket the request is charged to, and only tries to consume once
+// it is at the head of all of them. Since waiters join all their queues at
+// once, a waiter ahead of another in one queue is ahead of it in every queue
+// they share.
+func (bs *BucketStorageImpl) WaitService(ctx context.Context, body ConsumeServiceRequest) (accRes AccessStatusResponse, err error) {
+	log.Printf("event=wait_service status=started client_id=%s user_id=%s", body.ClientID, body.UserID)
+	charges, err := bs.chargesFor(body, true)
+	if err != nil {
+		return
+	}
+	bucketIDs := make([]string, 0, len(charges))
+	for _, c := range charges {
+		bucketIDs = append(bucketIDs, c.bucket.ID)
+	}
+
+	w := &waiter{ready: make(chan struct{}, 1)}
+	bs.joinWaitQueues(w, bucketIDs)
+	defer bs.leaveWaitQueues(w, bucketIDs)
+
+	deadline, hasDeadline := ctx.Deadline()
+	for {
+		if !bs.isWaitQueuesHead(w, bucketIDs) {
+			select {
+			case <-w.ready:
+				continue
+			case <-ctx.Done():
+				log.Printf("event=wait_service status=cancelled client_id=%s service_id=%s user_id=%s err=%q", body.ClientID, body.ServiceID, body.UserID, ctx.Err())
+				return accRes, ctx.Err()
+			}
+		}
+
+		accRes, err = consumeAll(charges)
+		if err != nil || accRes.IsAllowed {
+			break
+		}
+		if accRes.ExceedsLimit {
+			log.Printf("event=wait_service status=exceeds_limit client_id=%s service_id=%s user_id=%s denied_limit=%q", body.ClientID, body.ServiceID, body.UserID, accRes.DeniedLimit)
+			return accRes, nil
+		}
+		wait := max(time.Duration(accRes.RetryAfterMilliseconds)*time.Millisecond, time.Millisecond)
+		if hasDeadline && time.Now().Add(wait).After(deadline) {
+			log.Printf("event=wait_service status=deadline_exceeded client_id=%s service_id=%s user_id=%s retry_after=%d", body.ClientID, body.ServiceID, body.UserID, accRes.RetryAfterSeconds)
+			return accRes, nil
+		}
+		log.Printf("event=wait_service status=waiting client_id=%s service_id=%s user_id=%s wait_ms=%d", body.ClientID, body.ServiceID, body.UserID, wait.Milliseconds())
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("event=wait_service status=cancelled client_id=%s service_id=%s user_id=%s err=%q", body.ClientID, body.ServiceID, body.UserID, ctx.Err())
+			return accRes, ctx.Err()
+		}
+	}
+	if err != nil {
+		return
+	}
+	log.Printf("event=consume_tokens client_id=%s service_id=%s user_id=%s usage_amount=%d remaining=%d delay_ms=%d", body.ClientID, body.ServiceID, body.UserID, body.UsageAmount, accRes.Remaining, accRes.DelayMilliseconds)
+	return
+}
+
+func (bs *BucketStorageImpl) joinWaitQueues(w *waiter, bucketIDs []string) {
+	bs.waitersMu.Lock()
+	defer bs.waitersMu.Unlock()
+	for _, id := range bucketIDs {
+		bs.waiters[id] = append(bs.waiters[id], w)
+	}
+}
+
+// leaveWaitQueues removes the waiter from its queues and wakes up the
+// waiters that are now at the head of them.
+func (bs *BucketStorageImpl) leaveWaitQueues(w *waiter, bucketIDs []string) {
+	bs.waitersMu.Lock()
+	defer bs.waitersMu.Unlock()
+	for _, id := range bucketIDs {
+		queue := slices.DeleteFunc(bs.waiters[id], func(other *waiter) bool { return other == w })
+		if len(queue) == 0 {
+			delete(bs.waiters, id)
+			continue
+		}
+		bs.waiters[id] = queue
+		select {
+		case queue[0].ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (bs *BucketStorageImpl) isWaitQueuesHead(w *waiter, bucketIDs []string) bool {
+	bs.waitersMu.Lock()
+	defer bs.waitersMu.Unlock()
+	for _, id := range bucketIDs {
+		if bs.waiters[id][0] != w {
+			return false
+		}
+	}
+	return true
+}
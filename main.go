@@ -9,7 +9,6 @@ import (
 	"rate-limiter-go/config"
 	"rate-limiter-go/limiter"
 	"rate-limiter-go/persist"
-	"strings"
 	"time"
 
 	"google.golang.org/grpc"
@@ -37,17 +36,30 @@ func main() {
 	}
 	defer configFile.Close()
 	configParser := config.NewJsonParser()
-	config := configParser.Parse(configFile)
-	if config == nil {
+	config, err := configParser.Parse(configFile)
+	if err != nil {
 		log.Printf("event=failed_to_parse_config err=%q", err)
-		panic("failed to parse config")
+		panic(err)
 	}
 
 	log.Printf("event=init action=NewServiceRegistry")
 	mainServiceRegistry := limiter.NewServiceRegistry()
+	log.Printf("event=init action=NewRuleRegistry")
+	defaultRule := limiter.DefaultRule
+	if config.DefaultRule != nil {
+		defaultRule = config.DefaultRule.ToLimiterRule()
+	}
+	mainRuleRegistry := limiter.NewRuleRegistry(defaultRule)
+	log.Printf("event=init action=NewPoolRegistry")
+	mainPoolRegistry := limiter.NewPoolRegistry()
 	log.Printf("event=init action=NewBucketStorage")
-	mainBucketStorage := limiter.NewBucketStorage(mainServiceRegistry)
+	mainBucketStorage := limiter.NewBucketStorage(mainServiceRegistry, mainRuleRegistry, mainPoolRegistry)
+	log.Printf("event=init action=NewConcurrencyStorage")
+	mainConcurrencyStorage := limiter.NewConcurrencyStorage(mainRuleRegistry)
 
+	// serviceWriter and ruleWriter stay nil when persistence is disabled
+	var serviceWriter persist.FileWriter[api.ServiceRecord]
+	var ruleWriter persist.FileWriter[api.RuleSetRecord]
 	if !config.PersistenceSettings.Disabled {
 		var persistInterval uint8 = 10
 		if config.PersistenceSettings.IntervalSeconds > 0 {
@@ -55,28 +67,53 @@ func main() {
 		}
 
 		var persistence_dir = "./persistence_files"
-		persist.InitializePersistenceDir(persistence_dir)
+		persist.InitializePersistenceDir(persistence_dir, "semaphores", "reservations", "services", "rules")
 		jw := &persist.JsonWriter[limiter.Bucket]{}
+		semaphoreWriter := &persist.JsonWriter[limiter.Semaphore]{Dir: "semaphores"}
+		reservationWriter := &persist.JsonWriter[limiter.Reservation]{Dir: "reservations"}
+		serviceWriter = &persist.JsonWriter[api.ServiceRecord]{Dir: "services"}
+		ruleWriter = &persist.JsonWriter[api.RuleSetRecord]{Dir: "rules"}
 
 		// Load persisted buckets
-		entries, err := os.ReadDir(persistence_dir)
+		buckets, err := jw.LoadAll()
 		if err != nil {
 			panic(err)
 		}
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
+		savedBuckets := make(map[string]bool)
+		for _, bucket := range buckets {
+			err = mainBucketStorage.RestoreBucket(bucket)
+			if err != nil {
+				panic(err)
 			}
-			if strings.HasSuffix(entry.Name(), ".json") {
-				bucket, err := jw.LoadFromFile(entry.Name())
-				if err != nil {
-					log.Printf("level=error event=load_persisted_bucket status=error error=%q", err)
-				}
-				err = mainBucketStorage.RestoreBucket(bucket)
-				if err != nil {
-					panic(err)
-				}
+			savedBuckets[bucket.ID] = true
+		}
+
+		// Load persisted semaphores with the leases they held
+		semaphores, err := semaphoreWriter.LoadAll()
+		if err != nil {
+			panic(err)
+		}
+		for _, semaphore := range semaphores {
+			err = mainConcurrencyStorage.RestoreSemaphore(semaphore)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		// Load persisted reservations, after the buckets they hold tokens of.
+		// Reservations that expired while the service was down are released
+		// by the first sweep.
+		reservations, err := reservationWriter.LoadAll()
+		if err != nil {
+			panic(err)
+		}
+		savedReservations := make(map[string]bool)
+		for _, reservation := range reservations {
+			err = mainBucketStorage.RestoreReservation(reservation)
+			if err != nil {
+				panic(err)
 			}
+			savedReservations[reservation.ID] = true
 		}
 
 		// Save buckets on an interval
@@ -85,27 +122,110 @@ func main() {
 			for range ticker.C {
 				allBuckets := mainBucketStorage.GetAllBuckets()
 				log.Printf("level=info event=periodic_save start saving %d buckets", len(allBuckets))
+				currentBuckets := make(map[string]bool, len(allBuckets))
 				for _, b := range allBuckets {
 					b.Mu.Lock()
-					err := jw.SaveToFile(*b, b.ID)
+					err := jw.SaveToFile(b, b.ID)
 					if err != nil {
 						log.Printf("level=error event=persist_to_json status=error err=%q", err)
 					}
 					b.Mu.Unlock()
+					currentBuckets[b.ID] = true
+				}
+				// Deleted buckets must not be restored on the next start
+				savedBuckets = deleteStaleFiles(jw, savedBuckets, currentBuckets)
+				allSemaphores := mainConcurrencyStorage.GetAllSemaphores()
+				log.Printf("level=info event=periodic_save start saving %d semaphores", len(allSemaphores))
+				for _, sem := range allSemaphores {
+					sem.Mu.Lock()
+					err := semaphoreWriter.SaveToFile(sem, sem.ID)
+					if err != nil {
+						log.Printf("level=error event=persist_to_json status=error err=%q", err)
+					}
+					sem.Mu.Unlock()
+				}
+				allReservations := mainBucketStorage.GetAllReservations()
+				log.Printf("level=info event=periodic_save start saving %d reservations", len(allReservations))
+				openReservations := make(map[string]bool, len(allReservations))
+				for _, r := range allReservations {
+					err := reservationWriter.SaveToFile(&r, r.ID)
+					if err != nil {
+						log.Printf("level=error event=persist_to_json status=error err=%q", err)
+					}
+					openReservations[r.ID] = true
 				}
+				// Committed, cancelled and expired reservations must not be
+				// restored on the next start
+				savedReservations = deleteStaleFiles(reservationWriter, savedReservations, openReservations)
 			}
 		}()
 
 	}
 
+	// Release reservations that were neither committed nor cancelled in time
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		for range ticker.C {
+			released := mainBucketStorage.ReleaseExpiredReservations(time.Now())
+			if released > 0 {
+				log.Printf("level=info event=release_expired_reservations released=%d", released)
+			}
+		}
+	}()
+
+	configRules := make([]limiter.Rule, 0, len(config.Rules))
 	for _, rule := range config.Rules {
-		log.Printf("event=create_service id=%q usage_price_in_tokens=%d", rule.ServiceID, rule.UsagePrice)
-		_, err := mainServiceRegistry.CreateService((limiter.CreateServiceReqBody{
-			ID:                 rule.ServiceID,
-			UsagePriceInTokens: rule.UsagePrice,
-		}))
+		if limiter.IsPattern(rule.ServiceID) {
+			log.Printf("event=create_service status=skipped rule_id=%q service_id=%q reason=pattern", rule.ID, rule.ServiceID)
+		} else {
+			log.Printf("event=create_service id=%q usage_price_in_tokens=%d", rule.ServiceID, rule.UsagePrice)
+			_, err := mainServiceRegistry.CreateService((limiter.CreateServiceReqBody{
+				ID:                 rule.ServiceID,
+				UsagePriceInTokens: rule.UsagePrice,
+			}))
+			if err != nil {
+				log.Printf("event=create_service status=error error=%q", err)
+				panic(err)
+			}
+		}
+		err := mainRuleRegistry.AddRule(rule.ToLimiterRule())
+		if err != nil {
+			log.Printf("event=add_rule status=error error=%q", err)
+			panic(err)
+		}
+		configRules = append(configRules, rule.ToLimiterRule())
+	}
+
+	adminServer := &api.AdminServer{
+		ServiceRegistry: mainServiceRegistry,
+		RuleRegistry:    mainRuleRegistry,
+		BucketStorage:   mainBucketStorage,
+		ServiceWriter:   serviceWriter,
+		RuleWriter:      ruleWriter,
+		ConfigServices:  configServices(config),
+		ConfigRules:     configRules,
+	}
+
+	// Rules and services changed through the admin API override the ones of
+	// the config file, unless the file was edited since
+	err = adminServer.RestorePersisted()
+	if err != nil {
+		log.Printf("event=restore_persisted status=error error=%q", err)
+		panic(err)
+	}
+
+	for _, org := range config.Organizations {
+		err := mainRuleRegistry.SetOrganization(org.ID, org.ClientIDs)
 		if err != nil {
-			log.Printf("event=create_service status=error error=%q", err)
+			log.Printf("event=set_organization status=error error=%q", err)
+			panic(err)
+		}
+	}
+
+	for _, pool := range config.Pools {
+		_, err := mainPoolRegistry.CreatePool(pool.ToLimiterPool())
+		if err != nil {
+			log.Printf("event=create_pool status=error error=%q", err)
 			panic(err)
 		}
 	}
@@ -118,9 +238,16 @@ func main() {
 	}
 	grpcServer := grpc.NewServer()
 	api.RegisterRateLimiterServer(grpcServer, &api.Server{
-		BucketStorage:   mainBucketStorage,
-		ServiceRegistry: mainServiceRegistry,
+		BucketStorage:      mainBucketStorage,
+		ServiceRegistry:    mainServiceRegistry,
+		RuleRegistry:       mainRuleRegistry,
+		ConcurrencyStorage: mainConcurrencyStorage,
 	})
+	api.RegisterRateLimiterAdminServer(grpcServer, adminServer)
+
+	// Apply changes to the config file without a restart
+	reloader := newConfigReloader(os.Args[1], configParser, adminServer, config)
+	go reloader.watch(configPollInterval)
 
 	log.Printf("event=server status=listening port=%q", ":50051")
 
@@ -130,3 +257,19 @@ func main() {
 		panic(err)
 	}
 }
+
+// deleteStaleFiles deletes the files of the saved entities that are not
+// current anymore, and returns the ids of the entities that have a file now.
+func deleteStaleFiles[T any](w persist.FileWriter[T], saved map[string]bool, current map[string]bool) map[string]bool {
+	for id := range saved {
+		if current[id] {
+			continue
+		}
+		err := w.DeleteFile(id)
+		if err != nil {
+			// Try again on the next save
+			current[id] = true
+		}
+	}
+	return current
+}
@@ -2,26 +2,58 @@ package persist
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
+	"strings"
 )
 
+var ErrInvalidFileName = errors.New("file name should not be empty, start with '.' or contain a path separator")
+
 type FileWriter[T any] interface {
-	SaveToFile(entity T, filepath string) error
+	SaveToFile(entity *T, filepath string) error
 	LoadFromFile(filepath string) (*T, error)
+	LoadAll() ([]*T, error)
+	DeleteFile(filename string) error
 }
 
-type JsonWriter[T any] struct{}
+// JsonWriter stores every entity as a JSON file in the persistence
+// directory, or in its Dir subdirectory when Dir is set.
+type JsonWriter[T any] struct {
+	Dir string
+}
 
 var persistence_files_path string
 
-func (jw *JsonWriter[T]) SaveToFile(entity T, filename string) error {
-	filePath := persistence_files_path + "/" + filename + ".json"
+func (jw *JsonWriter[T]) dirPath() string {
+	if jw.Dir == "" {
+		return persistence_files_path
+	}
+	return persistence_files_path + "/" + jw.Dir
+}
+
+// validateFileName keeps entity ids from naming files outside the writer's
+// directory.
+func validateFileName(filename string) error {
+	if filename == "" || strings.HasPrefix(filename, ".") || strings.ContainsAny(filename, `/\`) {
+		log.Printf("level=error event=validate_file_name status=error filename=%q err=%q", filename, ErrInvalidFileName)
+		return ErrInvalidFileName
+	}
+	return nil
+}
+
+func (jw *JsonWriter[T]) SaveToFile(entity *T, filename string) error {
+	err := validateFileName(filename)
+	if err != nil {
+		return err
+	}
+	filePath := jw.dirPath() + "/" + filename + ".json"
 	fd, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
 	if err != nil {
 		log.Printf("level=error event=open_or_create_file status=error filepath=%s err=%q", filePath, err)
 		return err
 	}
+	defer fd.Close()
 	err = json.NewEncoder(fd).Encode(entity)
 	if err != nil {
 		log.Printf("level=error event=encode_json_to_file status=error err=%q", err)
@@ -31,13 +63,14 @@ func (jw *JsonWriter[T]) SaveToFile(entity T, filename string) error {
 }
 
 func (jw *JsonWriter[T]) LoadFromFile(filename string) (*T, error) {
-	filePath := persistence_files_path + "/" + filename
+	filePath := jw.dirPath() + "/" + filename
 	var entity T
 	fd, err := os.OpenFile(filePath, os.O_RDONLY, os.ModePerm)
 	if err != nil {
 		log.Printf("level=error event=read_file filepath=%s err=%q", filePath, err)
 		return nil, err
 	}
+	defer fd.Close()
 	err = json.NewDecoder(fd).Decode(&entity)
 	if err != nil {
 		log.Printf("level=error event=decode_json_from_file filepath=%s err=%q", filePath, err)
@@ -46,16 +79,62 @@ func (jw *JsonWriter[T]) LoadFromFile(filename string) (*T, error) {
 	return &entity, nil
 }
 
-func InitializePersistenceDir(dir string) {
-	_, err := os.Stat(dir)
-	if os.IsNotExist(err) {
-		err := os.Mkdir(dir, os.ModePerm)
-		if err != nil {
-			panic(err)
+// DeleteFile removes the file of an entity that no longer exists. Deleting a
+// file that does not exist is not an error.
+func (jw *JsonWriter[T]) DeleteFile(filename string) error {
+	err := validateFileName(filename)
+	if err != nil {
+		return err
+	}
+	filePath := jw.dirPath() + "/" + filename + ".json"
+	err = os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("level=error event=delete_file status=error filepath=%s err=%q", filePath, err)
+		return err
+	}
+	return nil
+}
+
+// LoadAll loads every JSON file of the writer's directory. Files that fail
+// to load are logged and skipped.
+func (jw *JsonWriter[T]) LoadAll() ([]*T, error) {
+	entries, err := os.ReadDir(jw.dirPath())
+	if err != nil {
+		log.Printf("level=error event=read_dir dir=%s err=%q", jw.dirPath(), err)
+		return nil, err
+	}
+	entities := make([]*T, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
 		}
-	} else {
+		entity, err := jw.LoadFromFile(entry.Name())
 		if err != nil {
-			panic(err)
+			continue
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// InitializePersistenceDir creates dir and the given subdirectories of it
+// when they do not exist yet.
+func InitializePersistenceDir(dir string, subdirs ...string) {
+	for _, d := range append([]string{""}, subdirs...) {
+		path := dir
+		if d != "" {
+			path = dir + "/" + d
+		}
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			err := os.Mkdir(path, os.ModePerm)
+			if err != nil {
+				panic(err)
+			}
+		} else {
+			if err != nil {
+				panic(err)
+			}
 		}
 	}
 	persistence_files_path = dir
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"rate-limiter-go/api"
+	"rate-limiter-go/config"
+	"rate-limiter-go/limiter"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// configReloader applies the rules and services of the config file again
+// whenever the file changes or the process receives SIGHUP. Organizations,
+// pools and persistence settings are only read on start.
+type configReloader struct {
+	path    string
+	parser  config.ConfigParser
+	admin   *api.AdminServer
+	current *config.Config
+	modTime time.Time
+}
+
+func newConfigReloader(path string, parser config.ConfigParser, admin *api.AdminServer, current *config.Config) *configReloader {
+	r := &configReloader{
+		path:    path,
+		parser:  parser,
+		admin:   admin,
+		current: current,
+	}
+	info, err := os.Stat(path)
+	if err == nil {
+		r.modTime = info.ModTime()
+	}
+	return r
+}
+
+// watch reloads the config file when its modification time changes and on
+// SIGHUP. It never returns.
+func (r *configReloader) watch(interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-hup:
+			r.reload("sighup")
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				log.Printf("level=error event=stat_config path=%q err=%q", r.path, err)
+				continue
+			}
+			if info.ModTime().Equal(r.modTime) {
+				continue
+			}
+			r.modTime = info.ModTime()
+			r.reload("file_changed")
+		}
+	}
+}
+
+// reload parses and validates the config file and swaps in its rules and
+// services. When the new config is invalid the current one is kept.
+func (r *configReloader) reload(reason string) {
+	log.Printf("event=reload_config path=%q reason=%q", r.path, reason)
+	next, err := r.parse()
+	if err != nil {
+		log.Printf("level=error event=reload_config status=error path=%q err=%q keeping=current", r.path, err)
+		return
+	}
+
+	// Parse validated the rules like the admin API does
+	rules := make([]limiter.Rule, 0, len(next.Rules))
+	for _, rule := range next.Rules {
+		rules = append(rules, rule.ToLimiterRule())
+	}
+	defaultRule := limiter.DefaultRule
+	if next.DefaultRule != nil {
+		defaultRule = next.DefaultRule.ToLimiterRule()
+	}
+
+	services := configServices(next)
+	oldServices := configServices(r.current)
+	var removedServiceIDs []string
+	for id := range oldServices {
+		if _, ok := services[id]; !ok {
+			removedServiceIDs = append(removedServiceIDs, id)
+		}
+	}
+	slices.Sort(removedServiceIDs)
+	serviceList := make([]limiter.Service, 0, len(services))
+	for _, service := range services {
+		serviceList = append(serviceList, service)
+	}
+
+	logRulesDiff(r.admin.RuleRegistry.GetRuleSet().Rules, rules)
+	logServicesDiff(oldServices, services)
+	if !reflect.DeepEqual(next.Organizations, r.current.Organizations) ||
+		!reflect.DeepEqual(next.Pools, r.current.Pools) ||
+		next.PersistenceSettings != r.current.PersistenceSettings {
+		log.Printf("level=warn event=reload_config path=%q msg=%q", r.path, "changes to organizations, pools and persistence settings take effect on restart")
+	}
+
+	version, err := r.admin.ApplyConfig(serviceList, removedServiceIDs, rules, defaultRule)
+	if err != nil {
+		// The new rules and services are in use, only saving them failed
+		log.Printf("level=error event=reload_config status=persist_error path=%q version=%d err=%q", r.path, version, err)
+	}
+	r.current = next
+	log.Printf("event=reload_config status=ok path=%q version=%d rules=%d services=%d", r.path, version, len(rules), len(serviceList))
+}
+
+func (r *configReloader) parse() (*config.Config, error) {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return r.parser.Parse(file)
+}
+
+// configServices returns the services the rules of a config create, by id.
+// Rules whose service id is a pattern do not create a service.
+func configServices(c *config.Config) map[string]limiter.Service {
+	services := make(map[string]limiter.Service)
+	for _, rule := range c.Rules {
+		if limiter.IsPattern(rule.ServiceID) {
+			continue
+		}
+		services[rule.ServiceID] = limiter.Service{
+			ID:                 rule.ServiceID,
+			UsagePriceInTokens: rule.UsagePrice,
+		}
+	}
+	return services
+}
+
+func logRulesDiff(oldRules []limiter.Rule, newRules []limiter.Rule) {
+	oldByID := make(map[string]limiter.Rule, len(oldRules))
+	for _, rule := range oldRules {
+		oldByID[rule.ID] = rule
+	}
+	newByID := make(map[string]limiter.Rule, len(newRules))
+	for _, rule := range newRules {
+		newByID[rule.ID] = rule
+	}
+	for _, rule := range newRules {
+		old, ok := oldByID[rule.ID]
+		if !ok {
+			log.Printf("event=reload_config diff=rule_added rule_id=%q level=%q service_id=%q", rule.ID, rule.Level, rule.ServiceID)
+		} else if !reflect.DeepEqual(old, rule) {
+			log.Printf("event=reload_config diff=rule_changed rule_id=%q level=%q service_id=%q", rule.ID, rule.Level, rule.ServiceID)
+		}
+	}
+	for _, rule := range oldRules {
+		if _, ok := newByID[rule.ID]; !ok {
+			log.Printf("event=reload_config diff=rule_removed rule_id=%q level=%q service_id=%q", rule.ID, rule.Level, rule.ServiceID)
+		}
+	}
+}
+
+func logServicesDiff(oldServices map[string]limiter.Service, newServices map[string]limiter.Service) {
+	for id, service := range newServices {
+		old, ok := oldServices[id]
+		if !ok {
+			log.Printf("event=reload_config diff=service_added service_id=%q usage_price_in_tokens=%d", id, service.UsagePriceInTokens)
+		} else if old.UsagePriceInTokens != service.UsagePriceInTokens {
+			log.Printf("event=reload_config diff=service_changed service_id=%q usage_price_in_tokens=%d old_usage_price_in_tokens=%d", id, service.UsagePriceInTokens, old.UsagePriceInTokens)
+		}
+	}
+	for id := range oldServices {
+		if _, ok := newServices[id]; !ok {
+			log.Printf("event=reload_config diff=service_removed service_id=%q", id)
+		}
+	}
+}